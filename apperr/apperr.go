@@ -0,0 +1,86 @@
+// Package apperr defines the typed errors shared by repositories, services
+// and controllers. Each error carries a stable machine-readable code and the
+// HTTP status it maps to, and is rendered as an RFC 7807 problem document.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	cause   error
+}
+
+func New(code string, status int, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+var (
+	ErrNotFound   = New("not_found", http.StatusNotFound, "resource not found")
+	ErrBadRequest = New("bad_request", http.StatusBadRequest, "bad request")
+	ErrValidation = New("validation_failed", http.StatusBadRequest, "request validation failed")
+	ErrConflict   = New("conflict", http.StatusConflict, "conflict")
+	ErrInternal   = New("internal_error", http.StatusInternalServerError, "internal server error")
+)
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches any *Error with the same code, so wrapped copies of a sentinel
+// still satisfy errors.Is.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e with cause attached.
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithMessage returns a copy of e with a more specific message.
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithFields returns a copy of e carrying per-field details.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &c
+}
+
+func BadRequest(message string) *Error {
+	return ErrBadRequest.WithMessage(message)
+}
+
+// From returns the *Error in err's chain, or an internal error wrapping err.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return ErrInternal.Wrap(err)
+}
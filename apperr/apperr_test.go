@@ -0,0 +1,50 @@
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppErrSuite(t *testing.T) {
+	eventNotFound := New("event_not_found", http.StatusNotFound, "event not found")
+
+	t.Run("Wrap_KeepsSentinelAndCause", func(t *testing.T) {
+		err := fmt.Errorf("load event: %w", eventNotFound.Wrap(ErrNotFound.Wrap(sql.ErrNoRows)))
+
+		assert.ErrorIs(t, err, eventNotFound)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Equal(t, "event_not_found", From(err).Code)
+	})
+
+	t.Run("WithMessage_DoesNotMutateSentinel", func(t *testing.T) {
+		err := ErrBadRequest.WithMessage("invalid event id")
+
+		assert.Equal(t, "invalid event id", err.Message)
+		assert.Equal(t, "bad request", ErrBadRequest.Message)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+
+	t.Run("From_UnknownErrorIsInternal", func(t *testing.T) {
+		appErr := From(errors.New("connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, appErr.Status)
+		assert.Equal(t, "internal server error", appErr.Problem("/events").Detail)
+	})
+
+	t.Run("Problem_IncludesFields", func(t *testing.T) {
+		err := ErrValidation.WithFields(FieldError{Field: "proposed_slots[0].end_time", Message: "must be after start_time"})
+		problem := err.Problem("/events")
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "Bad Request", problem.Title)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Len(t, problem.Errors, 1)
+		assert.Empty(t, ErrValidation.Fields)
+	})
+}
@@ -0,0 +1,31 @@
+package apperr
+
+import "net/http"
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document, extended with the error
+// code and any field-level errors.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Problem renders e for the response. Only the public message is exposed; the
+// wrapped cause stays in the logs.
+func (e *Error) Problem(instance string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Errors:   e.Fields,
+	}
+}
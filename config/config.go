@@ -0,0 +1,373 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ram-ks/meeting-service/logging"
+	"github.com/ram-ks/meeting-service/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is everything the service reads at startup. Values come from the
+// defaults, then the YAML file if one is given, then environment variables
+// named by the env tags, each overriding the last.
+type Config struct {
+	Server      ServerConfig      `yaml:"server"`
+	API         APIConfig         `yaml:"api"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Events      EventsConfig      `yaml:"events"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+}
+
+type ServerConfig struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// DrainDelay is how long the server keeps serving after /readyz starts
+	// failing on SIGTERM, so the load balancer notices before the listener
+	// closes. It counts against ShutdownTimeout.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// ShutdownTimeout bounds draining requests and stopping workers on
+	// SIGTERM; keep it under the platform's kill timeout.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// APIConfig covers the unversioned aliases of the /v1 routes, which answer
+// with Deprecation and Sunset headers.
+type APIConfig struct {
+	LegacyRoutes       bool      `yaml:"legacy_routes" env:"API_LEGACY_ROUTES"`
+	LegacyDeprecatedAt time.Time `yaml:"legacy_deprecated_at" env:"API_LEGACY_DEPRECATED_AT"`
+	LegacySunset       time.Time `yaml:"legacy_sunset" env:"API_LEGACY_SUNSET"`
+}
+
+type DatabaseConfig struct {
+	// URL holds the password, so it is redacted when printed.
+	URL             string        `yaml:"url" env:"DATABASE_URL"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	MigrationsDir   string        `yaml:"migrations_dir" env:"MIGRATIONS_DIR"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+type TracingConfig struct {
+	// Exporter is otlp, stdout or none. Left empty it becomes otlp when
+	// OTEL_EXPORTER_OTLP_ENDPOINT is set; the OTLP exporter reads its
+	// endpoint and headers from the standard OTEL_EXPORTER_OTLP_* variables.
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+type EventsConfig struct {
+	// Retention is how long a deleted event can be restored before it is
+	// purged.
+	Retention time.Duration `yaml:"retention" env:"EVENT_RETENTION"`
+	// DeadlineInterval is how often events are checked for respond_by
+	// deadlines to close and reminders to send.
+	DeadlineInterval time.Duration `yaml:"deadline_interval" env:"EVENT_DEADLINE_INTERVAL"`
+	// ReminderLead is how long before its deadline an event's pending
+	// participants are reminded; zero sends no reminders.
+	ReminderLead time.Duration `yaml:"reminder_lead" env:"EVENT_REMINDER_LEAD"`
+	// AutoFinalizeThreshold is the percentage of participants, if_needed
+	// answers included, who must be able to attend the best slot for an
+	// event with auto_finalize to be finalized on it when its responses
+	// close.
+	AutoFinalizeThreshold int `yaml:"auto_finalize_threshold" env:"EVENT_AUTO_FINALIZE_THRESHOLD"`
+}
+
+type IdempotencyConfig struct {
+	// Store is postgres or memory; memory only deduplicates within one
+	// instance.
+	Store string        `yaml:"store" env:"IDEMPOTENCY_STORE"`
+	TTL   time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Store is postgres, shared by all instances, or memory, per instance.
+	Store  string       `yaml:"store" env:"RATE_LIMIT_STORE"`
+	Routes []RouteLimit `yaml:"routes"`
+}
+
+// RouteLimit allows Requests per Period, with bursts of up to Burst
+// (Requests when zero), on one route, counted by Key: ip, organizer or
+// participant. Route is a method and a route template, as in
+// "POST /events/:id/availability". Participant rules go by an unverified
+// header and are advisory; the ip rule on the same route is what holds.
+type RouteLimit struct {
+	Route    string        `yaml:"route"`
+	Key      string        `yaml:"key"`
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst,omitempty"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			DrainDelay:        5 * time.Second,
+			ShutdownTimeout:   25 * time.Second,
+		},
+		API: APIConfig{
+			LegacyRoutes:       true,
+			LegacyDeprecatedAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			LegacySunset:       time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC),
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			MigrationsDir:   "./migrations",
+		},
+		Log: LogConfig{Level: "info"},
+		Events: EventsConfig{
+			Retention:             30 * 24 * time.Hour,
+			DeadlineInterval:      time.Minute,
+			ReminderLead:          24 * time.Hour,
+			AutoFinalizeThreshold: 80,
+		},
+		Idempotency: IdempotencyConfig{Store: "postgres", TTL: 24 * time.Hour},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "postgres",
+			Routes: []RouteLimit{
+				{Route: "POST /events/:id/availability", Key: "participant", Requests: 10, Period: time.Minute, Burst: 20},
+				{Route: "POST /events/:id/availability", Key: "ip", Requests: 60, Period: time.Minute},
+				{Route: "POST /events/:id/free-ranges", Key: "participant", Requests: 10, Period: time.Minute, Burst: 20},
+				{Route: "POST /events/:id/free-ranges", Key: "ip", Requests: 60, Period: time.Minute},
+				{Route: "POST /preferred-slots", Key: "participant", Requests: 10, Period: time.Minute, Burst: 20},
+				{Route: "POST /preferred-slots", Key: "ip", Requests: 30, Period: time.Minute},
+				{Route: "POST /events", Key: "organizer", Requests: 30, Period: time.Minute},
+			},
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file at path
+// (skipped when path is empty) and the environment. It does not validate.
+func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if cfg.Tracing.Exporter == "" {
+		if _, ok := lookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); ok {
+			cfg.Tracing.Exporter = tracing.ExporterOTLP
+		} else {
+			cfg.Tracing.Exporter = tracing.ExporterNone
+		}
+	}
+	return cfg, nil
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// applyEnv overrides every field that has an env tag and a set variable.
+func applyEnv(v reflect.Value, lookupEnv func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != timeType {
+			if err := applyEnv(field, lookupEnv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := t.Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		value, ok := lookupEnv(key)
+		if !ok || value == "" {
+			continue
+		}
+
+		switch {
+		case field.Type() == durationType:
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid duration %q", key, value)
+			}
+			field.SetInt(int64(d))
+		case field.Type() == timeType:
+			ts, err := parseTime(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid date %q", key, value)
+			}
+			field.Set(reflect.ValueOf(ts))
+		case field.Kind() == reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid integer %q", key, value)
+			}
+			field.SetInt(int64(n))
+		case field.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid boolean %q", key, value)
+			}
+			field.SetBool(b)
+		case field.Kind() == reflect.String:
+			field.SetString(value)
+		default:
+			return fmt.Errorf("%s: unsupported field type %s", key, field.Type())
+		}
+	}
+	return nil
+}
+
+// parseTime reads a date (2006-01-02, taken as UTC midnight) or an RFC 3339
+// timestamp.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535")
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"events.retention", c.Events.Retention},
+		{"events.deadline_interval", c.Events.DeadlineInterval},
+		{"idempotency.ttl", c.Idempotency.TTL},
+	} {
+		check(d.value > 0, "%s must be positive", d.name)
+	}
+
+	check(c.Server.DrainDelay >= 0 && c.Server.DrainDelay < c.Server.ShutdownTimeout,
+		"server.drain_delay must not be negative and must be less than server.shutdown_timeout")
+	check(c.Events.ReminderLead >= 0, "events.reminder_lead must not be negative")
+	check(c.Events.AutoFinalizeThreshold > 0 && c.Events.AutoFinalizeThreshold <= 100,
+		"events.auto_finalize_threshold must be between 1 and 100")
+
+	if c.API.LegacyRoutes {
+		check(!c.API.LegacyDeprecatedAt.IsZero() && !c.API.LegacySunset.IsZero(),
+			"api.legacy_deprecated_at and api.legacy_sunset are required while api.legacy_routes is on")
+		check(c.API.LegacySunset.After(c.API.LegacyDeprecatedAt), "api.legacy_sunset must be after api.legacy_deprecated_at")
+	}
+
+	check(c.Database.URL != "", "database.url is required (DATABASE_URL)")
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive")
+	check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns must be between 0 and max_open_conns")
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time must not be negative")
+	check(c.Database.MigrationsDir != "", "database.migrations_dir is required")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level must be debug, info, warn or error")
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be none, otlp or stdout"))
+	}
+	switch c.Idempotency.Store {
+	case "postgres", "memory":
+	default:
+		errs = append(errs, fmt.Errorf("idempotency.store must be postgres or memory"))
+	}
+
+	switch c.RateLimit.Store {
+	case "postgres", "memory":
+	default:
+		errs = append(errs, fmt.Errorf("rate_limit.store must be postgres or memory"))
+	}
+	for i, r := range c.RateLimit.Routes {
+		prefix := fmt.Sprintf("rate_limit.routes[%d]", i)
+		method, path, ok := strings.Cut(r.Route, " ")
+		check(ok && method != "" && strings.HasPrefix(path, "/"), "%s.route must be a method and a path, as in \"POST /events\"", prefix)
+		check(r.Key == "ip" || r.Key == "organizer" || r.Key == "participant", "%s.key must be ip, organizer or participant", prefix)
+		check(r.Requests > 0, "%s.requests must be positive", prefix)
+		check(r.Period > 0, "%s.period must be positive", prefix)
+		check(r.Burst >= 0, "%s.burst must not be negative", prefix)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy that is safe to print: the database password is
+// masked.
+func (c Config) Redacted() Config {
+	c.Database.URL = redactURL(c.Database.URL)
+	return c
+}
+
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// not a URL (a key=value DSN, say), so there's no telling where the
+		// password is; hide the whole thing
+		return "xxxxx"
+	}
+	return u.Redacted()
+}
+
+// YAML renders the redacted configuration.
+func (c Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envMap(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestConfigSuite(t *testing.T) {
+	t.Run("DefaultsWithURLAreValid", func(t *testing.T) {
+		cfg, err := load("", envMap(map[string]string{"DATABASE_URL": "postgres://u:p@db/meetings"}))
+		require.NoError(t, err)
+
+		assert.NoError(t, cfg.Validate())
+		assert.Equal(t, 8080, cfg.Server.Port)
+		assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+		assert.Equal(t, "none", cfg.Tracing.Exporter)
+	})
+
+	t.Run("EnvOverridesFile", func(t *testing.T) {
+		path := writeFile(t, `
+server:
+  port: 9000
+  write_timeout: 1m
+database:
+  url: postgres://file@db/meetings
+  max_open_conns: 50
+`)
+		cfg, err := load(path, envMap(map[string]string{
+			"PORT":                        "9100",
+			"DB_MAX_IDLE_CONNS":           "10",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, 9100, cfg.Server.Port)
+		assert.Equal(t, time.Minute, cfg.Server.WriteTimeout)
+		assert.Equal(t, "postgres://file@db/meetings", cfg.Database.URL)
+		assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+		assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+		assert.Equal(t, "otlp", cfg.Tracing.Exporter)
+	})
+
+	t.Run("ReadsDates", func(t *testing.T) {
+		cfg, err := load(writeFile(t, "api:\n  legacy_deprecated_at: 2026-01-01\n"), envMap(map[string]string{
+			"API_LEGACY_SUNSET": "2026-07-01T12:00:00Z",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), cfg.API.LegacyDeprecatedAt)
+		assert.Equal(t, time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), cfg.API.LegacySunset)
+	})
+
+	t.Run("RejectsUnknownFileKeys", func(t *testing.T) {
+		_, err := load(writeFile(t, "server:\n  prot: 9000\n"), envMap(nil))
+		assert.ErrorContains(t, err, "prot")
+	})
+
+	t.Run("RejectsMalformedEnv", func(t *testing.T) {
+		_, err := load("", envMap(map[string]string{"HTTP_READ_TIMEOUT": "soon"}))
+		assert.ErrorContains(t, err, "HTTP_READ_TIMEOUT")
+	})
+
+	t.Run("ValidateReportsEverything", func(t *testing.T) {
+		cfg := Default()
+		cfg.Server.Port = 0
+		cfg.Database.MaxIdleConns = 100
+		cfg.Log.Level = "loud"
+		cfg.Idempotency.Store = "redis"
+		cfg.Server.DrainDelay = cfg.Server.ShutdownTimeout
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		for _, want := range []string{"server.port", "server.drain_delay", "database.url", "database.max_idle_conns", "log.level", "tracing.exporter", "idempotency.store"} {
+			assert.Contains(t, err.Error(), want)
+		}
+	})
+
+	t.Run("ValidatesRateLimitRoutes", func(t *testing.T) {
+		cfg := Default()
+		cfg.Database.URL = "postgres://db/meetings"
+		cfg.RateLimit.Routes = []RouteLimit{{Route: "/events", Key: "session", Period: time.Minute}}
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rate_limit.routes[0].route")
+		assert.Contains(t, err.Error(), "rate_limit.routes[0].key")
+		assert.Contains(t, err.Error(), "rate_limit.routes[0].requests")
+	})
+
+	t.Run("PrintedConfigHidesPassword", func(t *testing.T) {
+		cfg := Default()
+		cfg.Database.URL = "postgres://app:hunter2@db:5432/meetings?sslmode=disable"
+
+		out, err := cfg.YAML()
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "hunter2")
+		assert.Contains(t, string(out), "postgres://app:xxxxx@db:5432/meetings")
+		assert.Contains(t, string(out), "drain_delay: 5s")
+		assert.Contains(t, string(out), "shutdown_timeout: 25s")
+
+		cfg.Database.URL = "host=db password=hunter2"
+		out, _ = cfg.YAML()
+		assert.NotContains(t, string(out), "hunter2")
+		// the original is untouched
+		assert.Equal(t, "host=db password=hunter2", cfg.Database.URL)
+	})
+}
@@ -2,8 +2,7 @@ package config
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	_ "github.com/lib/pq"
@@ -11,32 +10,33 @@ import (
 
 var db *sql.DB
 
-func ConnectDatabase() {
+func ConnectDatabase(cfg DatabaseConfig) {
 	var err error
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		log.Fatal("DATABASE_URL not set")
+	if cfg.URL == "" {
+		fatal("DATABASE_URL not set")
 	}
 
-	db, err = sql.Open("postgres", dbURL)
+	db, err = sql.Open("postgres", cfg.URL)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("failed to open database", "error", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	err = db.Ping()
 	if err != nil {
-		log.Fatal("Failed to connect to DB:", err)
+		fatal("failed to connect to database", "error", err)
 	}
 
-	fmt.Println("Database connected!")
+	slog.Info("database connected")
 }
 
 func GetDB() *sql.DB {
 	if db == nil {
-		log.Fatal("Database not initialized. Call ConnectDatabase() first.")
+		fatal("database not initialized; call ConnectDatabase first")
 	}
 	return db
 }
@@ -47,3 +47,8 @@ func CloseDatabase() error {
 	}
 	return nil
 }
+
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
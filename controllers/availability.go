@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,20 +20,18 @@ func NewAvailabilityController(availService service.AvailabilityService) *Availa
 func (ctrl *AvailabilityController) SubmitAvailability(context *gin.Context) {
 	eventID, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
 	var req model.SubmitAvailabilityRequest
 	if err := context.ShouldBindJSON(&req); err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(context, err)
 		return
 	}
 
-	if err := ctrl.availService.SubmitAvailability(context.Request.Context(), eventID, req); err != nil {
-		log.Printf("❌ [SubmitAvailability] Database error: %v", err)
-		log.Printf("❌ [SubmitAvailability] Error type: %T", err)
-		log.Printf("❌ [SubmitAvailability] Request data: %+v", req)
+	ctx := actorContext(context, "participant:"+req.ParticipantID.String())
+	if err := ctrl.availService.SubmitAvailability(ctx, eventID, req); err != nil {
 		handleServiceError(context, err)
 		return
 	}
@@ -45,7 +42,7 @@ func (ctrl *AvailabilityController) SubmitAvailability(context *gin.Context) {
 func (ctrl *AvailabilityController) GetAvailability(context *gin.Context) {
 	eventID, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
@@ -61,13 +58,13 @@ func (ctrl *AvailabilityController) GetAvailability(context *gin.Context) {
 func (ctrl *AvailabilityController) GetParticipantAvailability(context *gin.Context) {
 	eventID, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
 	participantID, err := uuid.Parse(context.Param("participant_id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid participant id"})
+		badRequest(context, "invalid participant id")
 		return
 	}
 
@@ -80,20 +77,42 @@ func (ctrl *AvailabilityController) GetParticipantAvailability(context *gin.Cont
 	context.JSON(http.StatusOK, gin.H{"availabilities": availabilities})
 }
 
+func (ctrl *AvailabilityController) GetParticipantHistory(context *gin.Context) {
+	eventID, err := uuid.Parse(context.Param("id"))
+	if err != nil {
+		badRequest(context, "invalid event id")
+		return
+	}
+
+	participantID, err := uuid.Parse(context.Param("participant_id"))
+	if err != nil {
+		badRequest(context, "invalid participant id")
+		return
+	}
+
+	history, err := ctrl.availService.GetParticipantHistory(context.Request.Context(), eventID, participantID)
+	if err != nil {
+		handleServiceError(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, history)
+}
+
 func (ctrl *AvailabilityController) UpdateAvailability(context *gin.Context) {
 	availabilityID, err := uuid.Parse(context.Param("availability_id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid availability id"})
+		badRequest(context, "invalid availability id")
 		return
 	}
 
 	var req model.UpdateAvailabilityRequest
 	if err := context.ShouldBindJSON(&req); err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(context, err)
 		return
 	}
 
-	availability, err := ctrl.availService.UpdateAvailability(context.Request.Context(), availabilityID, req)
+	availability, err := ctrl.availService.UpdateAvailability(actorContext(context, organizerActor(context)), availabilityID, req)
 	if err != nil {
 		handleServiceError(context, err)
 		return
@@ -105,11 +124,11 @@ func (ctrl *AvailabilityController) UpdateAvailability(context *gin.Context) {
 func (ctrl *AvailabilityController) DeleteAvailability(context *gin.Context) {
 	availabilityID, err := uuid.Parse(context.Param("availability_id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid availability id"})
+		badRequest(context, "invalid availability id")
 		return
 	}
 
-	if err := ctrl.availService.DeleteAvailability(context.Request.Context(), availabilityID); err != nil {
+	if err := ctrl.availService.DeleteAvailability(actorContext(context, organizerActor(context)), availabilityID); err != nil {
 		handleServiceError(context, err)
 		return
 	}
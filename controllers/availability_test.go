@@ -7,10 +7,13 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
 	"github.com/ram-ks/meeting-service/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -55,6 +58,22 @@ func (m *MockAvailabilityService) DeleteAvailability(ctx context.Context, availa
 	return args.Error(0)
 }
 
+func (m *MockAvailabilityService) GetParticipantHistory(ctx context.Context, eventID, participantID uuid.UUID) (*model.ParticipantAvailabilityHistory, error) {
+	args := m.Called(ctx, eventID, participantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ParticipantAvailabilityHistory), args.Error(1)
+}
+
+func (m *MockAvailabilityService) GetHeatmap(ctx context.Context, eventID uuid.UUID, bucket time.Duration) (*model.Heatmap, error) {
+	args := m.Called(ctx, eventID, bucket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Heatmap), args.Error(1)
+}
+
 // setupTestRouter creates a test router with the controller
 func setupTestRouter(ctrl *AvailabilityController) *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -65,7 +84,9 @@ func setupTestRouter(ctrl *AvailabilityController) *gin.Engine {
 	{
 		availability.POST("", ctrl.SubmitAvailability)
 		availability.GET("", ctrl.GetAvailability)
+		availability.GET("/heatmap", ctrl.GetHeatmap)
 		availability.GET("/:participant_id", ctrl.GetParticipantAvailability)
+		availability.GET("/:participant_id/history", ctrl.GetParticipantHistory)
 		availability.PUT("/:availability_id", ctrl.UpdateAvailability)
 		availability.DELETE("/:availability_id", ctrl.DeleteAvailability)
 	}
@@ -85,10 +106,12 @@ func TestAvailabilityControllerSuite(t *testing.T) {
 		req := model.SubmitAvailabilityRequest{
 			ParticipantID: uuid.New(),
 			Slots: []model.SlotAvailabilityRequest{
-				{SlotID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")},
-				{Status: model.AvailabilityStatusAvailable},
-				{AvailableFrom: &from},
-				{AvailableTo: &to},
+				{
+					SlotID:        uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
+					Status:        model.AvailabilityStatusPartial,
+					AvailableFrom: &from,
+					AvailableTo:   &to,
+				},
 			},
 		}
 
@@ -142,6 +165,69 @@ func TestAvailabilityControllerSuite(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("SubmitAvailability_FieldErrors", func(t *testing.T) {
+		mockService := new(MockAvailabilityService)
+		ctrl := NewAvailabilityController(mockService)
+		router := setupTestRouter(ctrl)
+
+		eventID := uuid.New()
+		body := []byte(`{
+			"participant_id": "` + uuid.NewString() + `",
+			"slots": [
+				{"slot_id": "550e8400-e29b-41d4-a716-446655440000", "status": "maybe"},
+				{"slot_id": "550e8400-e29b-41d4-a716-446655440001", "status": "partial", "available_from": "10am"}
+			]
+		}`)
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/events/"+eventID.String()+"/availability", bytes.NewBuffer(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem apperr.Problem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "slots[0].status", Message: "must be one of available, unavailable, partial, if_needed"},
+			{Field: "slots[1].available_from", Message: "must be an RFC 3339 timestamp"},
+		}, problem.Errors)
+		mockService.AssertNotCalled(t, "SubmitAvailability", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubmitAvailability_NotesTooLong", func(t *testing.T) {
+		mockService := new(MockAvailabilityService)
+		ctrl := NewAvailabilityController(mockService)
+		router := setupTestRouter(ctrl)
+
+		body, _ := json.Marshal(model.SubmitAvailabilityRequest{
+			ParticipantID: uuid.New(),
+			Note:          strings.Repeat("é", 1001),
+			Slots: []model.SlotAvailabilityRequest{
+				{SlotID: uuid.New(), Status: model.AvailabilityStatusAvailable, Note: strings.Repeat("a", 500)},
+				{SlotID: uuid.New(), Status: model.AvailabilityStatusAvailable, Note: strings.Repeat("a", 501)},
+			},
+		})
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/events/"+uuid.NewString()+"/availability", bytes.NewBuffer(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem apperr.Problem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.ElementsMatch(t, []apperr.FieldError{
+			{Field: "slots[1].note", Message: "must be at most 500 characters"},
+			{Field: "note", Message: "must be at most 1000 characters"},
+		}, problem.Errors)
+		mockService.AssertNotCalled(t, "SubmitAvailability", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("SubmitAvailability_ServiceError", func(t *testing.T) {
 		mockService := new(MockAvailabilityService)
 		ctrl := NewAvailabilityController(mockService)
@@ -153,10 +239,12 @@ func TestAvailabilityControllerSuite(t *testing.T) {
 		req := model.SubmitAvailabilityRequest{
 			ParticipantID: uuid.New(),
 			Slots: []model.SlotAvailabilityRequest{
-				{SlotID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")},
-				{Status: model.AvailabilityStatusAvailable},
-				{AvailableFrom: &from},
-				{AvailableTo: &to},
+				{
+					SlotID:        uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
+					Status:        model.AvailabilityStatusPartial,
+					AvailableFrom: &from,
+					AvailableTo:   &to,
+				},
 			},
 		}
 
@@ -438,6 +526,63 @@ func TestAvailabilityControllerSuite(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("GetHeatmap_CSV", func(t *testing.T) {
+		mockService := new(MockAvailabilityService)
+		ctrl := NewAvailabilityController(mockService)
+		router := setupTestRouter(ctrl)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		heatmap := &model.Heatmap{
+			EventID: eventID,
+			Participants: []model.HeatmapParticipant{
+				{ParticipantID: uuid.New(), Name: "Ana"},
+				{ParticipantID: uuid.New(), Name: "Bo"},
+			},
+			Slots: []model.HeatmapSlot{{
+				SlotID:    slotID,
+				StartTime: start,
+				EndTime:   start.Add(time.Hour),
+				Statuses:  []model.AvailabilityStatus{model.AvailabilityStatusAvailable, ""},
+				Totals:    model.HeatmapTotals{Available: 1, NoResponse: 1},
+			}},
+		}
+
+		mockService.On("GetHeatmap", mock.Anything, eventID, time.Duration(0)).Return(heatmap, nil)
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/events/"+eventID.String()+"/availability/heatmap", nil)
+		httpReq.Header.Set("Accept", "text/csv")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t,
+			"slot_id,start_time,end_time,Ana,Bo,available,partial,if_needed,unavailable,no_response\n"+
+				slotID.String()+",2026-03-02T09:00:00Z,2026-03-02T10:00:00Z,available,,1,0,0,0,1\n",
+			w.Body.String(),
+		)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetHeatmap_InvalidBucket", func(t *testing.T) {
+		mockService := new(MockAvailabilityService)
+		ctrl := NewAvailabilityController(mockService)
+		router := setupTestRouter(ctrl)
+
+		for _, bucket := range []string{"soon", "1m", "48h"} {
+			w := httptest.NewRecorder()
+			httpReq, _ := http.NewRequest("GET", "/events/"+uuid.New().String()+"/availability/heatmap?bucket="+bucket, nil)
+
+			router.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, bucket)
+		}
+		mockService.AssertNotCalled(t, "GetHeatmap", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("NewAvailabilityController", func(t *testing.T) {
 		mockService := new(MockAvailabilityService)
 		ctrl := NewAvailabilityController(mockService)
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/service"
+)
+
+type BlackoutController struct {
+	service service.BlackoutService
+}
+
+func NewBlackoutController(service service.BlackoutService) *BlackoutController {
+	return &BlackoutController{service: service}
+}
+
+func (ctrl *BlackoutController) CreateBlackout(c *gin.Context) {
+	var req model.CreateBlackoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	blackout, err := ctrl.service.Create(actorContext(c, organizerActor(c)), req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, blackout)
+}
+
+func (ctrl *BlackoutController) GetBlackoutsByEmail(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		badRequest(c, "email is required")
+		return
+	}
+
+	blackouts, err := ctrl.service.GetByEmail(c.Request.Context(), email)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blackouts": blackouts})
+}
+
+func (ctrl *BlackoutController) UpdateBlackout(c *gin.Context) {
+	blackoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid blackout id")
+		return
+	}
+
+	var req model.UpdateBlackoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	blackout, err := ctrl.service.Update(actorContext(c, organizerActor(c)), blackoutID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, blackout)
+}
+
+func (ctrl *BlackoutController) DeleteBlackout(c *gin.Context) {
+	blackoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid blackout id")
+		return
+	}
+
+	if err := ctrl.service.Delete(actorContext(c, organizerActor(c)), blackoutID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+// handleServiceError writes err as an application/problem+json response. Errors
+// that aren't an *apperr.Error become a 500 with the cause only logged.
+func handleServiceError(context *gin.Context, err error) {
+	appErr := apperr.From(err)
+	logServiceError(context, appErr, err)
+
+	context.Header("Content-Type", apperr.ProblemContentType)
+	context.Render(appErr.Status, render.JSON{Data: appErr.Problem(context.Request.URL.Path)})
+	context.Abort()
+}
+
+// logServiceError logs server errors with their cause; client errors are
+// only interesting when debugging.
+func logServiceError(context *gin.Context, appErr *apperr.Error, err error) {
+	level := slog.LevelDebug
+	if appErr.Status >= 500 {
+		level = slog.LevelError
+	}
+	slog.Log(context.Request.Context(), level, "request failed",
+		"method", context.Request.Method,
+		"route", context.FullPath(),
+		"status", appErr.Status,
+		"code", appErr.Code,
+		"error", err,
+	)
+}
+
+func badRequest(context *gin.Context, message string) {
+	handleServiceError(context, apperr.BadRequest(message))
+}
+
+// bindError reports a failed ShouldBind* call, listing each invalid field.
+func bindError(context *gin.Context, err error) {
+	handleServiceError(context, validation.FromBindError(err))
+}
@@ -1,35 +1,25 @@
 package controllers
 
 import (
-	"errors"
-	"fmt"
-	"log"
+	gocontext "context"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ram-ks/meeting-service/model"
 	models "github.com/ram-ks/meeting-service/model"
-	"github.com/ram-ks/meeting-service/repository"
-)
-
-var (
-	ErrEventNotFound        = errors.New("event not found")
-	ErrSlotNotFound         = errors.New("slot not found")
-	ErrInvalidStatus        = errors.New("invalid event status for this operation")
-	ErrSlotNotInEvent       = errors.New("slot does not belong to this event")
-	ErrInvalidTimeFormat    = errors.New("invalid time format")
-	ErrParticipantNotFound  = errors.New("participant not found")
-	ErrAvailabilityNotFound = errors.New("availability not found")
+	"github.com/ram-ks/meeting-service/service"
 )
 
 type EventController struct {
-	repo repository.EventRepository
+	eventService service.EventService
 }
 
-func NewEventController(repo repository.EventRepository) *EventController {
-	return &EventController{repo: repo}
+func NewEventController(eventService service.EventService) *EventController {
+	return &EventController{eventService: eventService}
 }
 
 func getOrganizerID(c *gin.Context) uuid.UUID {
@@ -41,114 +31,133 @@ func getOrganizerID(c *gin.Context) uuid.UUID {
 	return uuid.MustParse("00000000-0000-0000-0000-000000000001")
 }
 
-func parseTime(timeStr, timezone string) (time.Time, error) {
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		return time.Time{}, err
-	}
+// actorContext tags the request context with who is making the change, for
+// the audit log.
+func actorContext(c *gin.Context, actor string) gocontext.Context {
+	return service.WithActor(c.Request.Context(), actor)
+}
 
-	formats := []string{
-		"2006-01-02T15:04:05",
-		"2006-01-02T15:04:05Z07:00",
-		"2006-01-02 15:04:05",
-		time.RFC3339,
+func organizerActor(c *gin.Context) string {
+	return "organizer:" + getOrganizerID(c).String()
+}
+
+func (ctrl *EventController) CreateEvent(context *gin.Context) {
+	var req models.CreateEventRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		bindError(context, err)
+		return
 	}
 
-	for _, format := range formats {
-		if t, err := time.ParseInLocation(format, timeStr, loc); err == nil {
-			return t.UTC(), nil
-		}
+	ctx := actorContext(context, organizerActor(context))
+	event, err := ctrl.eventService.CreateEvent(ctx, getOrganizerID(context), req)
+	if err != nil {
+		handleServiceError(context, err)
+		return
 	}
 
-	return time.Time{}, errors.New("invalid time format")
+	slog.InfoContext(ctx, "event created", "event_id", event.ID)
+	context.JSON(http.StatusCreated, event)
 }
 
-func (ctrl *EventController) CreateEvent(context *gin.Context) {
-	var req models.CreateEventRequest
-	if err := context.ShouldBindJSON(&req); err != nil {
-		log.Printf("❌ [CreateEvent] Failed to parse request body: %v", err)
-		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (ctrl *EventController) ListEvents(context *gin.Context) {
+	var req models.ListEventsRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		bindError(context, err)
 		return
 	}
 
-	organizerID := getOrganizerID(context)
-	now := time.Now().UTC()
+	filter := models.EventFilter{
+		OrganizerID:      getOrganizerID(context),
+		Statuses:         req.Status,
+		Title:            strings.TrimSpace(req.Query),
+		ParticipantEmail: strings.TrimSpace(req.ParticipantEmail),
+		Sort:             models.EventSortCreatedDesc,
+		Limit:            req.Limit,
+	}
 
-	event := &models.Event{
-		ID:          uuid.New(),
-		Title:       req.Title,
-		Description: req.Description,
-		OrganizerID: organizerID,
-		Duration:    req.Duration,
-		Status:      models.EventStatusOpen,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+	if req.Sort != "" {
+		if !req.Sort.IsValid() {
+			badRequest(context, "invalid sort option")
+			return
+		}
+		filter.Sort = req.Sort
 	}
 
-	for _, slotReq := range req.ProposedSlots {
-		startTime, err := parseTime(slotReq.StartTime, slotReq.Timezone)
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
 		if err != nil {
-			fmt.Println("invalid time format")
+			badRequest(context, "invalid from date")
+			return
 		}
-		endTime, err := parseTime(slotReq.EndTime, slotReq.Timezone)
+		filter.SlotsFrom = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
 		if err != nil {
-			fmt.Println("invalid time format")
-		}
-
-		slot := models.TimeSlot{
-			ID:        uuid.New(),
-			EventID:   event.ID,
-			StartTime: startTime,
-			EndTime:   endTime,
-			Timezone:  slotReq.Timezone,
-			CreatedAt: now,
+			badRequest(context, "invalid to date")
+			return
 		}
-		event.ProposedSlots = append(event.ProposedSlots, slot)
+		filter.SlotsTo = &to
 	}
 
-	for _, pReq := range req.Participants {
-		participant := models.Participant{
-			ID:        uuid.New(),
-			EventID:   event.ID,
-			Email:     pReq.Email,
-			Name:      pReq.Name,
-			Status:    models.ParticipantStatusPending,
-			CreatedAt: now,
+	if req.Cursor != "" {
+		cursor, err := models.DecodeEventCursor(req.Cursor)
+		if err == nil && cursor.Sort != filter.Sort {
+			err = models.ErrInvalidCursor
+		}
+		if err != nil {
+			handleServiceError(context, err)
+			return
 		}
-		event.Participants = append(event.Participants, participant)
+		filter.Cursor = cursor
 	}
 
-	if err := ctrl.repo.Create(context.Request.Context(), event); err != nil {
-		log.Printf("❌ [CreateEvent] Database error: %v", err)
-		log.Printf("❌ [CreateEvent] Error type: %T", err)
-		log.Printf("❌ [CreateEvent] Request data: %+v", req)
-		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create item"})
+	page, err := ctrl.eventService.ListEvents(context.Request.Context(), filter)
+	if err != nil {
+		handleServiceError(context, err)
 		return
 	}
 
-	log.Printf("✅ [CreateEvent] Successfully created event: %s", event.ID)
-	context.JSON(http.StatusCreated, event)
+	context.JSON(http.StatusOK, page)
 }
 
-func (ctrl *EventController) ListEvents(context *gin.Context) {
-	organizerID := getOrganizerID(context)
-	events, err := ctrl.repo.List(context.Request.Context(), organizerID)
+func (ctrl *EventController) GetEvent(context *gin.Context) {
+	id, err := uuid.Parse(context.Param("id"))
+	if err != nil {
+		badRequest(context, "invalid event id")
+		return
+	}
+
+	event, err := ctrl.eventService.GetEvent(context.Request.Context(), id)
 	if err != nil {
 		handleServiceError(context, err)
 		return
 	}
+	context.JSON(http.StatusOK, event)
+}
+
+func (ctrl *EventController) DeleteEvent(context *gin.Context) {
+	id, err := uuid.Parse(context.Param("id"))
+	if err != nil {
+		badRequest(context, "invalid event id")
+		return
+	}
 
-	context.JSON(http.StatusOK, gin.H{"events": events})
+	if err := ctrl.eventService.DeleteEvent(actorContext(context, organizerActor(context)), id); err != nil {
+		handleServiceError(context, err)
+		return
+	}
+	context.JSON(http.StatusNoContent, nil)
 }
 
-func (ctrl *EventController) GetEvent(context *gin.Context) {
+func (ctrl *EventController) RestoreEvent(context *gin.Context) {
 	id, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
-	event, err := ctrl.repo.GetByID(context.Request.Context(), id)
+	event, err := ctrl.eventService.RestoreEvent(actorContext(context, organizerActor(context)), id)
 	if err != nil {
 		handleServiceError(context, err)
 		return
@@ -156,76 +165,76 @@ func (ctrl *EventController) GetEvent(context *gin.Context) {
 	context.JSON(http.StatusOK, event)
 }
 
-func (ctrl *EventController) DeleteEvent(context *gin.Context) {
+func (ctrl *EventController) FinalizeEvent(context *gin.Context) {
 	id, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
-	if err := ctrl.repo.Delete(context.Request.Context(), id); err != nil {
+	var req model.FinalizeEventRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		bindError(context, err)
+		return
+	}
+
+	event, err := ctrl.eventService.FinalizeEvent(actorContext(context, organizerActor(context)), id, req)
+	if err != nil {
 		handleServiceError(context, err)
 		return
 	}
-	context.JSON(http.StatusNoContent, nil)
+	context.JSON(http.StatusOK, event)
 }
 
 func (ctrl *EventController) UpdateEvent(context *gin.Context) {
 	id, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
 	var req model.UpdateEventRequest
 	if err := context.ShouldBindJSON(&req); err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(context, err)
 		return
 	}
 
-	event, err := ctrl.repo.GetByID(context.Request.Context(), id)
+	event, err := ctrl.eventService.UpdateEvent(actorContext(context, organizerActor(context)), id, req)
 	if err != nil {
 		handleServiceError(context, err)
+		return
 	}
 
-	if event.Status == model.EventStatusFinalized || event.Status == model.EventStatusCancelled {
-		handleServiceError(context, err)
-	}
+	context.JSON(http.StatusOK, event)
+}
 
-	if req.Title != nil {
-		event.Title = *req.Title
+func (ctrl *EventController) GetHistory(context *gin.Context) {
+	eventID, err := uuid.Parse(context.Param("id"))
+	if err != nil {
+		badRequest(context, "invalid event id")
+		return
 	}
-	if req.Description != nil {
-		event.Description = *req.Description
+
+	var req model.ListHistoryRequest
+	if err := context.ShouldBindQuery(&req); err != nil {
+		bindError(context, err)
+		return
 	}
-	if req.Duration != nil {
-		event.Duration = *req.Duration
+
+	var cursor *model.AuditCursor
+	if req.Cursor != "" {
+		cursor, err = model.DecodeAuditCursor(req.Cursor)
+		if err != nil {
+			handleServiceError(context, err)
+			return
+		}
 	}
 
-	if err := ctrl.repo.Update(context.Request.Context(), event); err != nil {
+	page, err := ctrl.eventService.GetHistory(context.Request.Context(), eventID, req.Limit, cursor)
+	if err != nil {
 		handleServiceError(context, err)
+		return
 	}
 
-	context.JSON(http.StatusOK, event)
-}
-
-func handleServiceError(context *gin.Context, err error) {
-	switch err {
-	case ErrEventNotFound:
-		context.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
-	case ErrSlotNotFound:
-		context.JSON(http.StatusNotFound, gin.H{"error": "slot not found"})
-	case ErrInvalidStatus:
-		context.JSON(http.StatusConflict, gin.H{"error": "invalid event status for this operation"})
-	case ErrSlotNotInEvent:
-		context.JSON(http.StatusBadRequest, gin.H{"error": "slot does not belong to this event"})
-	case ErrInvalidTimeFormat:
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid time format"})
-	case ErrParticipantNotFound:
-		context.JSON(http.StatusNotFound, gin.H{"error": "participant not found"})
-	case ErrAvailabilityNotFound:
-		context.JSON(http.StatusNotFound, gin.H{"error": "availability not found"})
-	default:
-		context.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-	}
+	context.JSON(http.StatusOK, page)
 }
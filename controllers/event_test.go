@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEventService is a mock implementation of EventService
+type MockEventService struct {
+	mock.Mock
+}
+
+func (m *MockEventService) CreateEvent(ctx context.Context, organizerID uuid.UUID, req model.CreateEventRequest) (*model.Event, error) {
+	args := m.Called(ctx, organizerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventService) ListEvents(ctx context.Context, filter model.EventFilter) (*model.EventPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EventPage), args.Error(1)
+}
+
+func (m *MockEventService) GetEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, req model.UpdateEventRequest) (*model.Event, error) {
+	args := m.Called(ctx, eventID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventService) DeleteEvent(ctx context.Context, eventID uuid.UUID) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
+
+func (m *MockEventService) RestoreEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventService) PurgeDeletedEvents(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEventService) FinalizeEvent(ctx context.Context, eventID uuid.UUID, req model.FinalizeEventRequest) (*model.Event, error) {
+	args := m.Called(ctx, eventID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventService) GetHistory(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error) {
+	args := m.Called(ctx, eventID, limit, cursor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuditPage), args.Error(1)
+}
+
+func setupEventTestRouter(ctrl *EventController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", ctrl.ListEvents)
+	return router
+}
+
+func TestEventControllerSuite(t *testing.T) {
+	t.Run("ListEvents_StatusFilter", func(t *testing.T) {
+		mockService := new(MockEventService)
+		ctrl := NewEventController(mockService)
+		router := setupEventTestRouter(ctrl)
+
+		page := &model.EventPage{Events: []model.Event{}}
+		mockService.On("ListEvents", mock.Anything, mock.MatchedBy(func(f model.EventFilter) bool {
+			return assert.ObjectsAreEqual([]model.EventStatus{model.EventStatusOpen, model.EventStatusClosed}, f.Statuses)
+		})).Return(page, nil)
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/events?status=open&status=closed", nil)
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("ListEvents_UnknownStatus", func(t *testing.T) {
+		mockService := new(MockEventService)
+		ctrl := NewEventController(mockService)
+		router := setupEventTestRouter(ctrl)
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/events?status=open&status=archived", nil)
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem apperr.Problem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "status[1]", Message: "must be one of draft, open, closed, finalized, cancelled"},
+		}, problem.Errors)
+		mockService.AssertNotCalled(t, "ListEvents", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/service"
+)
+
+type FreeRangeController struct {
+	service service.FreeRangeService
+}
+
+func NewFreeRangeController(service service.FreeRangeService) *FreeRangeController {
+	return &FreeRangeController{service: service}
+}
+
+func (ctrl *FreeRangeController) SubmitFreeRanges(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid event id")
+		return
+	}
+
+	var req model.SubmitFreeRangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	ctx := actorContext(c, "participant:"+req.ParticipantID.String())
+	ranges, err := ctrl.service.SubmitFreeRanges(ctx, eventID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"free_ranges": ranges})
+}
+
+func (ctrl *FreeRangeController) GetFreeRanges(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid event id")
+		return
+	}
+
+	ranges, err := ctrl.service.GetFreeRanges(c.Request.Context(), eventID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"free_ranges": ranges})
+}
+
+func (ctrl *FreeRangeController) GetFreeWindows(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid event id")
+		return
+	}
+
+	windows, err := ctrl.service.GetFreeWindows(c.Request.Context(), eventID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
+
+func (ctrl *FreeRangeController) PromoteWindow(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid event id")
+		return
+	}
+
+	var req model.PromoteWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	slot, err := ctrl.service.PromoteWindow(actorContext(c, organizerActor(c)), eventID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, slot)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+const (
+	minHeatmapBucket = 5 * time.Minute
+	maxHeatmapBucket = 24 * time.Hour
+)
+
+// GetHeatmap writes the grid of answers for an event, in CSV or JSON by
+// ?format= or the Accept header. ?bucket= (a duration such as 15m) adds
+// counts of who is free per bucket of time; in CSV those rows replace the
+// grid.
+func (ctrl *AvailabilityController) GetHeatmap(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid event id")
+		return
+	}
+
+	var bucket time.Duration
+	if param := c.Query("bucket"); param != "" {
+		bucket, err = time.ParseDuration(param)
+		if err != nil || bucket < minHeatmapBucket || bucket > maxHeatmapBucket {
+			badRequest(c, fmt.Sprintf("bucket must be a duration between %s and %s", minHeatmapBucket, maxHeatmapBucket))
+			return
+		}
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	heatmap, err := ctrl.availService.GetHeatmap(c.Request.Context(), eventID, bucket)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, heatmap)
+		return
+	}
+
+	c.Header("Content-Type", csvContentType+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="heatmap-%s.csv"`, eventID))
+	c.Status(http.StatusOK)
+	if bucket > 0 {
+		err = writeHeatmapBuckets(c.Writer, heatmap.Buckets)
+	} else {
+		err = writeHeatmapSlots(c.Writer, heatmap)
+	}
+	if err != nil {
+		// the status is already sent; all that's left is to log it
+		slog.WarnContext(c.Request.Context(), "heatmap export failed", "error", err)
+	}
+}
+
+// writeHeatmapSlots writes a row per slot with a column per participant,
+// headed by their name, followed by the slot's totals.
+func writeHeatmapSlots(w io.Writer, heatmap *model.Heatmap) error {
+	writer := csv.NewWriter(w)
+	header := []string{"slot_id", "start_time", "end_time"}
+	for _, p := range heatmap.Participants {
+		header = append(header, p.Name)
+	}
+	header = append(header, "available", "partial", "if_needed", "unavailable", "no_response")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, slot := range heatmap.Slots {
+		record := []string{slot.SlotID.String(), slot.StartTime.Format(time.RFC3339), slot.EndTime.Format(time.RFC3339)}
+		for _, status := range slot.Statuses {
+			record = append(record, string(status))
+		}
+		record = append(record,
+			strconv.Itoa(slot.Totals.Available),
+			strconv.Itoa(slot.Totals.Partial),
+			strconv.Itoa(slot.Totals.IfNeeded),
+			strconv.Itoa(slot.Totals.Unavailable),
+			strconv.Itoa(slot.Totals.NoResponse),
+		)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeHeatmapBuckets(w io.Writer, buckets []model.HeatmapBucket) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"start_time", "end_time", "free", "if_needed", "total"}); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		record := []string{
+			b.StartTime.Format(time.RFC3339),
+			b.EndTime.Format(time.RFC3339),
+			strconv.Itoa(b.Free),
+			strconv.Itoa(b.IfNeeded),
+			strconv.Itoa(b.Total),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,16 +20,13 @@ func NewPreferredSlotController(service service.PreferredSlotService) *Preferred
 func (ctrl *PreferredSlotController) CreatePreferredSlot(c *gin.Context) {
 	var req model.CreatePreferredSlotRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
-	slot, err := ctrl.service.Create(c.Request.Context(), req)
+	slot, err := ctrl.service.Create(actorContext(c, organizerActor(c)), req)
 	if err != nil {
-		log.Printf("❌ [CreateSlot] Database error: %v", err)
-		log.Printf("❌ [CreateSlot] Error type: %T", err)
-		log.Printf("❌ [CreateSlot] Request data: %+v", req)
-		handlePreferredSlotError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -40,13 +36,13 @@ func (ctrl *PreferredSlotController) CreatePreferredSlot(c *gin.Context) {
 func (ctrl *PreferredSlotController) GetPreferredSlotsByEmail(c *gin.Context) {
 	email := c.Param("email")
 	if email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		badRequest(c, "email is required")
 		return
 	}
 
 	slots, err := ctrl.service.GetByEmail(c.Request.Context(), email)
 	if err != nil {
-		handlePreferredSlotError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -56,19 +52,19 @@ func (ctrl *PreferredSlotController) GetPreferredSlotsByEmail(c *gin.Context) {
 func (ctrl *PreferredSlotController) UpdatePreferredSlot(c *gin.Context) {
 	slotID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot id"})
+		badRequest(c, "invalid slot id")
 		return
 	}
 
 	var req model.UpdatePreferredSlotRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
-	slot, err := ctrl.service.Update(c.Request.Context(), slotID, req)
+	slot, err := ctrl.service.Update(actorContext(c, organizerActor(c)), slotID, req)
 	if err != nil {
-		handlePreferredSlotError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -78,23 +74,30 @@ func (ctrl *PreferredSlotController) UpdatePreferredSlot(c *gin.Context) {
 func (ctrl *PreferredSlotController) DeletePreferredSlot(c *gin.Context) {
 	slotID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot id"})
+		badRequest(c, "invalid slot id")
 		return
 	}
 
-	if err := ctrl.service.Delete(c.Request.Context(), slotID); err != nil {
-		handlePreferredSlotError(c, err)
+	if err := ctrl.service.Delete(actorContext(c, organizerActor(c)), slotID); err != nil {
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
 
-func handlePreferredSlotError(c *gin.Context, err error) {
-	switch err {
-	case service.ErrPreferredSlotNotFound:
-		c.JSON(http.StatusNotFound, gin.H{"error": "preferred slot not found"})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+func (ctrl *PreferredSlotController) BootstrapPreferredSlots(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		badRequest(c, "email is required")
+		return
+	}
+
+	slots, err := ctrl.service.Bootstrap(actorContext(c, organizerActor(c)), email)
+	if err != nil {
+		handleServiceError(c, err)
+		return
 	}
+
+	c.JSON(http.StatusCreated, gin.H{"preferred_slots": slots})
 }
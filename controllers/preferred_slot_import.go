@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+const (
+	// SkippedSlotsHeader reports how many preferred slots an export left
+	// out because they don't fit the row form.
+	SkippedSlotsHeader = "X-Skipped-Slots"
+
+	maxImportBody   = 4 << 20
+	maxExportEmails = 100
+	csvContentType  = "text/csv"
+)
+
+var ErrUnsupportedMediaType = apperr.New("unsupported_media_type", http.StatusUnsupportedMediaType, "body must be application/json or text/csv")
+
+// ImportPreferredSlots creates preferred slots from a JSON array or CSV
+// file of rows. ?mode=atomic (the default) imports nothing if any row is
+// invalid; ?mode=best_effort imports the valid rows and reports the rest.
+func (ctrl *PreferredSlotController) ImportPreferredSlots(c *gin.Context) {
+	mode := model.ImportMode(c.DefaultQuery("mode", string(model.ImportModeAtomic)))
+	if !mode.IsValid() {
+		badRequest(c, "mode must be atomic or best_effort")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody)
+	var rows []model.PreferredSlotRow
+	var err error
+	switch c.ContentType() {
+	case csvContentType:
+		rows, err = readSlotRows(body)
+	case "application/json", "":
+		err = json.NewDecoder(body).Decode(&rows)
+	default:
+		handleServiceError(c, ErrUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		handleServiceError(c, apperr.BadRequest("malformed import: "+err.Error()).Wrap(err))
+		return
+	}
+
+	result, err := ctrl.service.Import(actorContext(c, organizerActor(c)), rows, mode)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Failed > 0 {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+// ExportPreferredSlots writes the preferred slots of ?emails= (comma
+// separated or repeated) as rows that ImportPreferredSlots accepts, in CSV
+// or JSON by ?format= or the Accept header.
+func (ctrl *PreferredSlotController) ExportPreferredSlots(c *gin.Context) {
+	var emails []string
+	for _, param := range c.QueryArray("emails") {
+		for _, email := range strings.Split(param, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				emails = append(emails, email)
+			}
+		}
+	}
+	if len(emails) == 0 {
+		badRequest(c, "emails is required")
+		return
+	}
+	if len(emails) > maxExportEmails {
+		badRequest(c, fmt.Sprintf("at most %d emails can be exported at once", maxExportEmails))
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	rows, skipped, err := ctrl.service.Export(c.Request.Context(), emails)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Header(SkippedSlotsHeader, strconv.Itoa(skipped))
+	if format == "json" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	c.Header("Content-Type", csvContentType+"; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="preferred-slots.csv"`)
+	c.Status(http.StatusOK)
+	if err := writeSlotRows(c.Writer, rows); err != nil {
+		// the status is already sent; all that's left is to log it
+		slog.WarnContext(c.Request.Context(), "preferred slot export failed", "error", err)
+	}
+}
+
+// exportFormat picks json or csv by ?format= or, failing that, the Accept
+// header. It answers 400 and returns false for any other format.
+func exportFormat(c *gin.Context) (string, bool) {
+	format := c.Query("format")
+	switch format {
+	case "":
+		format = "json"
+		if c.NegotiateFormat(gin.MIMEJSON, csvContentType) == csvContentType {
+			format = "csv"
+		}
+	case "json", "csv":
+	default:
+		badRequest(c, "format must be json or csv")
+		return "", false
+	}
+	return format, true
+}
+
+// readSlotRows reads CSV with a header row naming the columns, in any
+// order. The days and level columns may be left out.
+func readSlotRows(r io.Reader) ([]model.PreferredSlotRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, known := rowField(&model.PreferredSlotRow{}, name); !known {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns[name] = i
+	}
+	for _, name := range model.PreferredSlotRowColumns {
+		if _, ok := columns[name]; !ok && name != "days" && name != "level" {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var rows []model.PreferredSlotRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var row model.PreferredSlotRow
+		for name, i := range columns {
+			field, _ := rowField(&row, name)
+			*field = record[i]
+		}
+		rows = append(rows, row)
+	}
+}
+
+func writeSlotRows(w io.Writer, rows []model.PreferredSlotRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(model.PreferredSlotRowColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{row.Email, row.Days, row.Start, row.End, row.Timezone, row.Level}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func rowField(row *model.PreferredSlotRow, column string) (*string, bool) {
+	switch column {
+	case "email":
+		return &row.Email, true
+	case "days":
+		return &row.Days, true
+	case "start":
+		return &row.Start, true
+	case "end":
+		return &row.End, true
+	case "timezone":
+		return &row.Timezone, true
+	case "level":
+		return &row.Level, true
+	}
+	return nil, false
+}
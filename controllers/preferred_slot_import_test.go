@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPreferredSlotService struct {
+	mock.Mock
+}
+
+func (m *MockPreferredSlotService) Create(ctx context.Context, req model.CreatePreferredSlotRequest) (*model.PreferredSlot, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PreferredSlot), args.Error(1)
+}
+
+func (m *MockPreferredSlotService) GetByEmail(ctx context.Context, email string) ([]model.PreferredSlot, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PreferredSlot), args.Error(1)
+}
+
+func (m *MockPreferredSlotService) Update(ctx context.Context, slotID uuid.UUID, req model.UpdatePreferredSlotRequest) (*model.PreferredSlot, error) {
+	args := m.Called(ctx, slotID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PreferredSlot), args.Error(1)
+}
+
+func (m *MockPreferredSlotService) Delete(ctx context.Context, slotID uuid.UUID) error {
+	args := m.Called(ctx, slotID)
+	return args.Error(0)
+}
+
+func (m *MockPreferredSlotService) Bootstrap(ctx context.Context, email string) ([]model.PreferredSlot, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PreferredSlot), args.Error(1)
+}
+
+func (m *MockPreferredSlotService) Import(ctx context.Context, rows []model.PreferredSlotRow, mode model.ImportMode) (*model.PreferredSlotImportResult, error) {
+	args := m.Called(ctx, rows, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PreferredSlotImportResult), args.Error(1)
+}
+
+func (m *MockPreferredSlotService) Export(ctx context.Context, emails []string) ([]model.PreferredSlotRow, int, error) {
+	args := m.Called(ctx, emails)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]model.PreferredSlotRow), args.Int(1), args.Error(2)
+}
+
+func TestPreferredSlotImportSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func() (*MockPreferredSlotService, *gin.Engine) {
+		mockService := new(MockPreferredSlotService)
+		ctrl := NewPreferredSlotController(mockService)
+		router := gin.New()
+		router.POST("/preferred-slots/import", ctrl.ImportPreferredSlots)
+		router.GET("/preferred-slots/export", ctrl.ExportPreferredSlots)
+		return mockService, router
+	}
+
+	t.Run("Import_CSV_ColumnsInAnyOrder", func(t *testing.T) {
+		mockService, router := setup()
+
+		body := "\ufefftimezone,Email,start,end,days\n" +
+			"Europe/Berlin,alice@example.com,09:00,12:00,\"MO,WE\"\n" +
+			"UTC,bob@example.com,13:00,17:00,\n"
+		expected := []model.PreferredSlotRow{
+			{Email: "alice@example.com", Days: "MO,WE", Start: "09:00", End: "12:00", Timezone: "Europe/Berlin"},
+			{Email: "bob@example.com", Start: "13:00", End: "17:00", Timezone: "UTC"},
+		}
+		result := &model.PreferredSlotImportResult{Mode: model.ImportModeBestEffort, Imported: 1, Failed: 1}
+		mockService.On("Import", mock.Anything, expected, model.ImportModeBestEffort).Return(result, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/preferred-slots/import?mode=best_effort", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Import_JSON_DefaultsToAtomic", func(t *testing.T) {
+		mockService, router := setup()
+
+		rows := []model.PreferredSlotRow{{Email: "alice@example.com", Start: "09:00", End: "12:00", Timezone: "UTC"}}
+		mockService.On("Import", mock.Anything, rows, model.ImportModeAtomic).
+			Return(&model.PreferredSlotImportResult{Mode: model.ImportModeAtomic, Imported: 1}, nil)
+
+		body, _ := json.Marshal(rows)
+		req := httptest.NewRequest(http.MethodPost, "/preferred-slots/import", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Import_RejectsBadInput", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			url         string
+			contentType string
+			body        string
+			status      int
+		}{
+			{"unknown mode", "/preferred-slots/import?mode=some", "application/json", "[]", http.StatusBadRequest},
+			{"unknown column", "/preferred-slots/import", "text/csv", "email,start,end,timezone,colour\n", http.StatusBadRequest},
+			{"missing column", "/preferred-slots/import", "text/csv", "email,start,end\n", http.StatusBadRequest},
+			{"ragged row", "/preferred-slots/import", "text/csv", "email,start,end,timezone\na@example.com,09:00\n", http.StatusBadRequest},
+			{"xml", "/preferred-slots/import", "application/xml", "<rows/>", http.StatusUnsupportedMediaType},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockService, router := setup()
+
+				req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", tt.contentType)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.status, w.Code)
+				mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Export_CSV", func(t *testing.T) {
+		mockService, router := setup()
+
+		rows := []model.PreferredSlotRow{{Email: "alice@example.com", Days: "MO,WE", Start: "09:00", End: "12:00", Timezone: "Europe/Berlin", Level: "acceptable"}}
+		mockService.On("Export", mock.Anything, []string{"alice@example.com", "bob@example.com", "carol@example.com"}).Return(rows, 2, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/preferred-slots/export?emails=alice@example.com,%20bob@example.com&emails=carol@example.com", nil)
+		req.Header.Set("Accept", "text/csv")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(SkippedSlotsHeader))
+		assert.Equal(t, "email,days,start,end,timezone,level\nalice@example.com,\"MO,WE\",09:00,12:00,Europe/Berlin,acceptable\n", w.Body.String())
+	})
+
+	t.Run("Export_RequiresEmails", func(t *testing.T) {
+		_, router := setup()
+
+		req := httptest.NewRequest(http.MethodGet, "/preferred-slots/export", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,11 +20,16 @@ func NewRecommendationController(schedulerService service.SchedulerService) *Rec
 func (ctrl *RecommendationController) GetRecommendations(context *gin.Context) {
 	eventID, err := uuid.Parse(context.Param("id"))
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		badRequest(context, "invalid event id")
 		return
 	}
 
-	recommendations, err := ctrl.schedulerService.GetRecommendations(context.Request.Context(), eventID)
+	getRecommendations := ctrl.schedulerService.GetRecommendations
+	if explain, _ := strconv.ParseBool(context.Query("explain")); explain {
+		getRecommendations = ctrl.schedulerService.ExplainRecommendations
+	}
+
+	recommendations, err := getRecommendations(context.Request.Context(), eventID)
 	if err != nil {
 		handleServiceError(context, err)
 		return
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
 	"github.com/ram-ks/meeting-service/model"
 	"github.com/ram-ks/meeting-service/service"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +31,14 @@ func (m *MockSchedulerService) GetRecommendations(ctx context.Context, eventID u
 	return args.Get(0).(*model.RecommendationResponse), args.Error(1)
 }
 
+func (m *MockSchedulerService) ExplainRecommendations(ctx context.Context, eventID uuid.UUID) (*model.RecommendationResponse, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RecommendationResponse), args.Error(1)
+}
+
 func setupRecommendationTestRouter(ctrl *RecommendationController) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -99,7 +109,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					TotalParticipants:   3,
 					AvailabilityPercent: 100,
 					PreferredCount:      2,
-					PreferredPercent:    66.67,
+					PreferenceScore:     66.67,
 					IsPerfectMatch:      true,
 				},
 			},
@@ -145,7 +155,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					TotalParticipants:   3,
 					AvailabilityPercent: 66.67,
 					PreferredCount:      2,
-					PreferredPercent:    66.67,
+					PreferenceScore:     66.67,
 					IsPerfectMatch:      false,
 				},
 				{
@@ -154,7 +164,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					TotalParticipants:   3,
 					AvailabilityPercent: 33.33,
 					PreferredCount:      0,
-					PreferredPercent:    0,
+					PreferenceScore:     0,
 					IsPerfectMatch:      false,
 				},
 			},
@@ -197,7 +207,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					TotalParticipants:   3,
 					AvailabilityPercent: 100,
 					PreferredCount:      1,
-					PreferredPercent:    33.33,
+					PreferenceScore:     33.33,
 					IsPerfectMatch:      true,
 				},
 			},
@@ -208,7 +218,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					TotalParticipants:   3,
 					AvailabilityPercent: 66.67,
 					PreferredCount:      3,
-					PreferredPercent:    100,
+					PreferenceScore:     100,
 					IsPerfectMatch:      false,
 				},
 			},
@@ -253,6 +263,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 					AvailabilityPercent: 50,
 					PreferredCount:      2,
 					PreferredPercent:    100,
+					PreferenceScore:     100,
 					IsPerfectMatch:      false,
 				},
 			},
@@ -272,6 +283,7 @@ func TestRecommendationControllerSuite(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 2, response.BestMatches[0].PreferredCount)
 		assert.Equal(t, float64(100), response.BestMatches[0].PreferredPercent)
+		assert.Equal(t, float64(100), response.BestMatches[0].PreferenceScore)
 
 		mockService.AssertExpectations(t)
 	})
@@ -311,16 +323,22 @@ func TestRecommendationControllerSuite(t *testing.T) {
 
 		eventID := uuid.New()
 
-		// service.ErrEventNotFound is not handled by handleServiceError, falls through to 500
-		mockService.On("GetRecommendations", mock.Anything, eventID).Return(nil, service.ErrEventNotFound)
+		mockService.On("GetRecommendations", mock.Anything, eventID).Return(nil, fmt.Errorf("lookup: %w", service.ErrEventNotFound))
 
 		w := httptest.NewRecorder()
 		httpReq, _ := http.NewRequest("GET", "/events/"+eventID.String()+"/recommendations", nil)
 
 		router.ServeHTTP(w, httpReq)
 
-		// Currently returns 500 as handleServiceError doesn't recognize service.ErrEventNotFound
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, apperr.ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem apperr.Problem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "event_not_found", problem.Code)
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "event not found", problem.Detail)
+		assert.Equal(t, "/events/"+eventID.String()+"/recommendations", problem.Instance)
 
 		mockService.AssertExpectations(t)
 	})
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/ram-ks/meeting-service/service"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment so proxies
+// don't close the connection.
+const streamKeepAlive = 15 * time.Second
+
+type StreamController struct {
+	eventService service.EventService
+	broker       pubsub.Broker
+	keepAlive    time.Duration
+	closing      chan struct{}
+	closeOnce    sync.Once
+}
+
+func NewStreamController(eventService service.EventService, broker pubsub.Broker) *StreamController {
+	return &StreamController{
+		eventService: eventService,
+		broker:       broker,
+		keepAlive:    streamKeepAlive,
+		closing:      make(chan struct{}),
+	}
+}
+
+// Close ends every open stream so a draining server isn't held up by them;
+// clients reconnect to another instance.
+func (ctrl *StreamController) Close() {
+	ctrl.closeOnce.Do(func() { close(ctrl.closing) })
+}
+
+// StreamEvent pushes an event's updates as Server-Sent Events until the
+// client disconnects. Each SSE event is named after the message type and
+// carries the message as JSON.
+func (ctrl *StreamController) StreamEvent(context *gin.Context) {
+	eventID, err := uuid.Parse(context.Param("id"))
+	if err != nil {
+		badRequest(context, "invalid event id")
+		return
+	}
+
+	if _, err := ctrl.eventService.GetEvent(context.Request.Context(), eventID); err != nil {
+		handleServiceError(context, err)
+		return
+	}
+
+	// the stream outlives the server's write timeout; not every writer
+	// supports deadlines, which is fine
+	_ = http.NewResponseController(context.Writer).SetWriteDeadline(time.Time{})
+
+	sub := ctrl.broker.Subscribe(eventID)
+	defer sub.Close()
+
+	keepAlive := time.NewTicker(ctrl.keepAlive)
+	defer keepAlive.Stop()
+
+	context.Header("Content-Type", "text/event-stream")
+	context.Header("Cache-Control", "no-cache")
+	context.Header("Connection", "keep-alive")
+	// stop nginx-style proxies from buffering the stream
+	context.Header("X-Accel-Buffering", "no")
+
+	context.Stream(func(w io.Writer) bool {
+		select {
+		case <-context.Request.Context().Done():
+			return false
+		case <-ctrl.closing:
+			return false
+		case msg, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			msg.Origin = ""
+			msg.Trace = nil
+			context.SSEvent(string(msg.Type), msg)
+			return true
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/service"
+)
+
+type WorkingHoursController struct {
+	service service.WorkingHoursService
+}
+
+func NewWorkingHoursController(service service.WorkingHoursService) *WorkingHoursController {
+	return &WorkingHoursController{service: service}
+}
+
+func (ctrl *WorkingHoursController) PutWorkingHours(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		badRequest(c, "email is required")
+		return
+	}
+
+	var req model.PutWorkingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	profile, err := ctrl.service.Put(actorContext(c, organizerActor(c)), email, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (ctrl *WorkingHoursController) GetWorkingHours(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		badRequest(c, "email is required")
+		return
+	}
+
+	profile, err := ctrl.service.GetByEmail(c.Request.Context(), email)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (ctrl *WorkingHoursController) DeleteWorkingHours(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		badRequest(c, "email is required")
+		return
+	}
+
+	if err := ctrl.service.Delete(actorContext(c, organizerActor(c)), email); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
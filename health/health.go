@@ -0,0 +1,155 @@
+// Package health backs the liveness and readiness probes and supervises the
+// background workers whose state readiness reports.
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+
+	// pingTimeout bounds the database check so a hung connection can't hold
+	// the probe past the platform's own timeout.
+	pingTimeout = 2 * time.Second
+)
+
+var (
+	errMigrationsPending = errors.New("migrations have not run")
+	errDraining          = errors.New("shutting down")
+)
+
+// Pinger is the part of *sql.DB the readiness check uses.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Checker tracks what the service needs before it should take traffic: a
+// reachable database, applied migrations and running workers. It stops
+// reporting ready as soon as shutdown begins so the load balancer drains
+// the instance.
+type Checker struct {
+	db Pinger
+
+	mu         sync.RWMutex
+	migrations error
+	workers    map[string]error
+	draining   bool
+}
+
+func NewChecker(db Pinger) *Checker {
+	return &Checker{db: db, migrations: errMigrationsPending, workers: make(map[string]error)}
+}
+
+// MigrationsDone records the outcome of the startup migrations.
+func (c *Checker) MigrationsDone(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.migrations = err
+}
+
+func (c *Checker) WorkerStarted(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers[name] = nil
+}
+
+// WorkerFailed marks a worker that exited before it was asked to stop.
+func (c *Checker) WorkerFailed(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers[name] = err
+}
+
+// Drain makes every later readiness check fail.
+func (c *Checker) Drain() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+}
+
+// Ready runs the checks. The database is pinged on every call; the rest is
+// state recorded by startup and the workers.
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Check)}
+	set := func(name string, err error) {
+		if err != nil {
+			report.Status = StatusFail
+			report.Checks[name] = Check{Status: StatusFail, Error: err.Error()}
+			return
+		}
+		report.Checks[name] = Check{Status: StatusOK}
+	}
+
+	c.mu.RLock()
+	if c.draining {
+		set("shutdown", errDraining)
+	}
+	set("migrations", c.migrations)
+	for name, err := range c.workers {
+		set("worker:"+name, err)
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	set("database", c.db.PingContext(ctx))
+
+	return report
+}
+
+// Livez reports that the process is up and serving. It deliberately checks
+// nothing else, so a database outage doesn't get the instance restarted.
+func Livez() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+	}
+}
+
+// Readyz serves checker's report, with 503 when any check fails.
+func Readyz(checker *Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
+// Health serves the deprecated /health response from checker's report, so
+// it gets the same bounded database ping and fails while draining.
+func Health(checker *Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+		body := gin.H{"status": "healthy", "database": "connected"}
+		if db := report.Checks["database"]; db.Status != StatusOK {
+			body["database"] = "connection failed"
+			body["error"] = db.Error
+		}
+		if report.Status != StatusOK {
+			body["status"] = "unhealthy"
+			c.JSON(http.StatusServiceUnavailable, body)
+			return
+		}
+		c.JSON(http.StatusOK, body)
+	}
+}
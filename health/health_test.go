@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error { return f(ctx) }
+
+var healthyDB = pingerFunc(func(context.Context) error { return nil })
+
+func TestCheckerSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(checker *Checker) (int, Report) {
+		router := gin.New()
+		router.GET("/readyz", Readyz(checker))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+		var report Report
+		json.Unmarshal(w.Body.Bytes(), &report)
+		return w.Code, report
+	}
+
+	t.Run("ReadyOnceMigrated", func(t *testing.T) {
+		checker := NewChecker(healthyDB)
+		checker.WorkerStarted("purger")
+
+		code, report := serve(checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusFail, report.Checks["migrations"].Status)
+
+		checker.MigrationsDone(nil)
+		code, report = serve(checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, StatusOK, report.Checks["worker:purger"].Status)
+		assert.Equal(t, StatusOK, report.Checks["database"].Status)
+	})
+
+	t.Run("DatabaseDown", func(t *testing.T) {
+		checker := NewChecker(pingerFunc(func(context.Context) error { return errors.New("connection refused") }))
+		checker.MigrationsDone(nil)
+
+		code, report := serve(checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, Check{Status: StatusFail, Error: "connection refused"}, report.Checks["database"])
+	})
+
+	t.Run("DrainingIsUnready", func(t *testing.T) {
+		checker := NewChecker(healthyDB)
+		checker.MigrationsDone(nil)
+		checker.Drain()
+
+		code, report := serve(checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusFail, report.Checks["shutdown"].Status)
+	})
+
+	t.Run("LegacyHealthFollowsReadiness", func(t *testing.T) {
+		legacy := func(checker *Checker) (int, map[string]string) {
+			router := gin.New()
+			router.GET("/health", Health(checker))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+			var body map[string]string
+			json.Unmarshal(w.Body.Bytes(), &body)
+			return w.Code, body
+		}
+
+		checker := NewChecker(healthyDB)
+		checker.MigrationsDone(nil)
+		code, body := legacy(checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]string{"status": "healthy", "database": "connected"}, body)
+
+		checker.Drain()
+		code, body = legacy(checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "unhealthy", body["status"])
+
+		var deadline bool
+		down := NewChecker(pingerFunc(func(ctx context.Context) error {
+			_, deadline = ctx.Deadline()
+			return errors.New("connection refused")
+		}))
+		down.MigrationsDone(nil)
+		code, body = legacy(down)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, map[string]string{"status": "unhealthy", "database": "connection failed", "error": "connection refused"}, body)
+		assert.True(t, deadline, "the ping is bounded")
+	})
+
+	t.Run("LivezChecksNothing", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/livez", Livez())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/livez", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestWorkersSuite(t *testing.T) {
+	t.Run("StopsInReverseOrder", func(t *testing.T) {
+		var mu sync.Mutex
+		var stopped []string
+		loop := func(name string) func(context.Context) error {
+			return func(ctx context.Context) error {
+				<-ctx.Done()
+				mu.Lock()
+				stopped = append(stopped, name)
+				mu.Unlock()
+				return nil
+			}
+		}
+
+		checker := NewChecker(healthyDB)
+		workers := NewWorkers(checker)
+		workers.Go("listener", loop("listener"))
+		workers.Go("notifier", loop("notifier"))
+
+		require.NoError(t, workers.Stop(context.Background()))
+		assert.Equal(t, []string{"notifier", "listener"}, stopped)
+
+		checker.MigrationsDone(nil)
+		assert.Equal(t, StatusOK, checker.Ready(context.Background()).Status)
+	})
+
+	t.Run("EarlyExitMarksUnready", func(t *testing.T) {
+		checker := NewChecker(healthyDB)
+		checker.MigrationsDone(nil)
+		workers := NewWorkers(checker)
+		workers.Go("listener", func(context.Context) error { return errors.New("listen failed") })
+
+		assert.Eventually(t, func() bool {
+			return checker.Ready(context.Background()).Checks["worker:listener"].Error == "listen failed"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("StopGivesUp", func(t *testing.T) {
+		workers := NewWorkers(nil)
+		release := make(chan struct{})
+		defer close(release)
+		workers.Go("stuck", func(context.Context) error { <-release; return nil })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, workers.Stop(ctx), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var errWorkerExited = errors.New("worker exited")
+
+// Workers runs named background loops, each with its own context, so they
+// can be stopped one at a time.
+type Workers struct {
+	checker *Checker
+	running []*worker
+}
+
+type worker struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWorkers reports worker state to checker, which may be nil.
+func NewWorkers(checker *Checker) *Workers {
+	return &Workers{checker: checker}
+}
+
+// Go starts run in a goroutine. If it returns before Stop it is logged and,
+// with a checker, marks the service unready.
+func (w *Workers) Go(name string, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	wk := &worker{name: name, cancel: cancel, done: make(chan struct{})}
+	w.running = append(w.running, wk)
+	if w.checker != nil {
+		w.checker.WorkerStarted(name)
+	}
+
+	go func() {
+		defer close(wk.done)
+		err := run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errWorkerExited
+		}
+		slog.Error("background worker stopped unexpectedly", "worker", name, "error", err)
+		if w.checker != nil {
+			w.checker.WorkerFailed(name, err)
+		}
+	}()
+}
+
+// Stop cancels the workers in the reverse of their start order and waits for
+// each before moving on, so a worker stops before the ones it was started
+// after and may rely on. It gives up when ctx ends.
+func (w *Workers) Stop(ctx context.Context) error {
+	for i := len(w.running) - 1; i >= 0; i-- {
+		wk := w.running[i]
+		wk.cancel()
+		select {
+		case <-wk.done:
+			slog.Info("background worker stopped", "worker", wk.name)
+		case <-ctx.Done():
+			return fmt.Errorf("stop %s: %w", wk.name, ctx.Err())
+		}
+	}
+	return nil
+}
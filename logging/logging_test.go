@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLoggingSuite(t *testing.T) {
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	t.Run("RedactsEmailsInMessageAndAttributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, slog.LevelInfo)
+
+		logger.Info("invited jane.doe@example.com",
+			"email", "Bob@Example.org",
+			"error", errors.New("duplicate key for ann@example.com"),
+		)
+
+		record := decode(t, &buf)
+		assert.Equal(t, "invited j***@example.com", record["msg"])
+		assert.Equal(t, "B***@Example.org", record["email"])
+		assert.Equal(t, "duplicate key for a***@example.com", record["error"])
+	})
+
+	t.Run("AddsRequestIDFromContext", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, slog.LevelInfo)
+
+		logger.InfoContext(WithRequestID(context.Background(), "req-123"), "handled")
+
+		assert.Equal(t, "req-123", decode(t, &buf)["request_id"])
+	})
+
+	t.Run("AddsTraceIDsFromContext", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, slog.LevelInfo)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		logger.InfoContext(ctx, "handled")
+
+		record := decode(t, &buf)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+		assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	})
+
+	t.Run("RespectsLevel", func(t *testing.T) {
+		var buf bytes.Buffer
+		level, err := ParseLevel("WARN")
+		assert.NoError(t, err)
+
+		New(&buf, level).Info("hidden")
+
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("ParseLevel_RejectsUnknown", func(t *testing.T) {
+		_, err := ParseLevel("verbose")
+		assert.Error(t, err)
+	})
+}
@@ -1,46 +1,70 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/ram-ks/meeting-service/config"
 	"github.com/ram-ks/meeting-service/controllers"
+	"github.com/ram-ks/meeting-service/health"
+	"github.com/ram-ks/meeting-service/logging"
+	"github.com/ram-ks/meeting-service/metrics"
+	"github.com/ram-ks/meeting-service/middleware"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
 	"github.com/ram-ks/meeting-service/repository"
+	"github.com/ram-ks/meeting-service/router"
 	"github.com/ram-ks/meeting-service/service"
+	"github.com/ram-ks/meeting-service/tracing"
 )
 
-func runMigrations(db *sql.DB) error {
-	log.Println("🔄 Running database migrations...")
+func runMigrations(db *sql.DB, dir string) error {
+	slog.Info("running database migrations", "dir", dir)
 
-	// Check if migration file exists
-	migrationPath := "./migrations/001_create_tables_up.sql"
-	if _, err := os.Stat(migrationPath); os.IsNotExist(err) {
-		log.Printf("⚠️  Migration file not found at %s, skipping...", migrationPath)
+	// Migrations are applied in filename order; each one must be safe to re-run
+	migrationFiles, err := filepath.Glob(filepath.Join(dir, "*_up.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migration files: %w", err)
+	}
+	if len(migrationFiles) == 0 {
+		slog.Warn("no migration files found, skipping")
 		return nil
 	}
+	sort.Strings(migrationFiles)
 
-	// Read migration file
-	migrationSQL, err := os.ReadFile(migrationPath)
-	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
-	}
+	for _, migrationPath := range migrationFiles {
+		// Read migration file
+		migrationSQL, err := os.ReadFile(migrationPath)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", migrationPath, err)
+		}
 
-	// Execute migration
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
-		// Check if error is because tables already exist
-		if isTableExistsError(err) {
-			log.Println("ℹ️ Tables already exist, skipping migration")
-			return nil
+		// Execute migration
+		if _, err := db.Exec(string(migrationSQL)); err != nil {
+			// Check if error is because tables already exist
+			if isTableExistsError(err) {
+				slog.Info("migration already applied, skipping", "file", migrationPath)
+				continue
+			}
+			return fmt.Errorf("failed to run migration %s: %w", migrationPath, err)
 		}
-		return fmt.Errorf("failed to run migration: %w", err)
 	}
 
-	log.Println("✅ Migrations completed successfully")
+	slog.Info("migrations completed")
 	return nil
 }
 
@@ -53,97 +77,301 @@ func isTableExistsError(err error) bool {
 		strings.Contains(errStr, "duplicate")
 }
 
-func healthCheck(c *gin.Context) {
-	db := config.GetDB()
+// newIdempotencyStore picks the configured store. The in-memory store only
+// deduplicates within one instance.
+func newIdempotencyStore(db *sql.DB, cfg config.IdempotencyConfig) repository.IdempotencyStore {
+	if cfg.Store == "memory" {
+		return repository.NewMemoryIdempotencyStore()
+	}
+	return repository.NewIdempotencyStore(db)
+}
 
-	if db == nil {
-		c.JSON(503, gin.H{
-			"status":   "unhealthy",
-			"database": "not initialized",
-		})
-		return
+func newRateLimitStore(db *sql.DB, cfg config.RateLimitConfig) repository.RateLimitStore {
+	if cfg.Store == "memory" {
+		return repository.NewMemoryRateLimitStore()
 	}
+	return repository.NewRateLimitStore(db)
+}
 
-	// Ping database
-	if err := db.Ping(); err != nil {
-		c.JSON(503, gin.H{
-			"status":   "unhealthy",
-			"database": "connection failed",
-			"error":    err.Error(),
-		})
-		return
+// rateLimitRules converts the configured limits, which name unversioned
+// routes, for every path the route is served at; the paths share buckets.
+// It also returns how long a bucket takes to refill completely under the
+// slowest rule; a bucket idle for that long is full and can be dropped.
+func rateLimitRules(cfg config.RateLimitConfig, legacyRoutes bool) ([]middleware.RateLimitRule, time.Duration) {
+	var rules []middleware.RateLimitRule
+	refill := time.Minute
+	for _, r := range cfg.Routes {
+		method, route, _ := strings.Cut(r.Route, " ")
+		limit := model.RateLimit{Requests: r.Requests, Period: r.Period, Burst: r.Burst}
+		for _, path := range router.Paths(route, legacyRoutes) {
+			rules = append(rules, middleware.RateLimitRule{
+				Method: method,
+				Route:  path,
+				Bucket: r.Route,
+				Key:    middleware.RateLimitKey(r.Key),
+				Limit:  limit,
+			})
+		}
+		if d := r.Period * time.Duration(limit.Size()) / time.Duration(r.Requests); d > refill {
+			refill = d
+		}
 	}
+	return rules, refill
+}
 
-	c.JSON(200, gin.H{
-		"status":   "healthy",
-		"database": "connected",
-	})
+func sweepRateLimitBuckets(ctx context.Context, store repository.RateLimitStore, idle time.Duration) {
+	ticker := time.NewTicker(idle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := store.DeleteIdle(ctx, time.Now().UTC().Add(-idle)); err != nil {
+				slog.Error("failed to delete idle rate limit buckets", "error", err)
+			} else if n > 0 {
+				slog.Debug("deleted idle rate limit buckets", "count", n)
+			}
+		}
+	}
 }
 
-func main() {
-	config.ConnectDatabase()
+func sweepIdempotencyKeys(ctx context.Context, store repository.IdempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	db := config.GetDB()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := store.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+				slog.Error("failed to delete expired idempotency keys", "error", err)
+			} else if n > 0 {
+				slog.Info("deleted expired idempotency keys", "count", n)
+			}
+		}
+	}
+}
+
+// loadConfig reads the configuration named by the flags, prints it and exits
+// for --print-config, and exits on an invalid configuration.
+func loadConfig() *config.Config {
+	path := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file; environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
 
-	fmt.Printf("DB is nil: %v\n", db == nil)
-	if db != nil {
-		fmt.Println("✅ DB connection is valid!")
+	cfg, err := config.Load(*path)
+	if err != nil {
+		slog.Error("failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	validationErr := cfg.Validate()
 
-		// doesn't work on managed postgres, hence adding a fallback to run migrations from here
-		if err := runMigrations(db); err != nil {
-			log.Printf("❌ Migration error: %v", err)
-			log.Println("⚠️  App will continue, but database operations may fail")
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			slog.Error("failed to render configuration", "error", err)
+			os.Exit(1)
 		}
-	} else {
-		log.Println("❌ Database connection failed!")
-		log.Println("⚠️  App will start but all database operations will fail")
+		os.Stdout.Write(out)
+	}
+	if validationErr != nil {
+		slog.Error("invalid configuration", "error", validationErr)
+		os.Exit(1)
 	}
+	if *printConfig {
+		os.Exit(0)
+	}
+	return cfg
+}
+
+// setupTracing starts the configured exporter. The returned function flushes
+// pending spans.
+func setupTracing(ctx context.Context, cfg config.TracingConfig) func(context.Context) error {
+	shutdown, err := tracing.Setup(ctx, cfg.Exporter)
+	if err != nil {
+		slog.Error("tracing disabled", "error", err)
+		return func(context.Context) error { return nil }
+	}
+	return shutdown
+}
+
+func main() {
+	cfg := loadConfig()
+
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logging.Setup(os.Stdout, level)
+
+	shutdownTracing := setupTracing(context.Background(), cfg.Tracing)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	config.ConnectDatabase(cfg.Database)
+
+	db := config.GetDB()
+	checker := health.NewChecker(db)
+
+	// doesn't work on managed postgres, hence adding a fallback to run migrations from here
+	err := runMigrations(db, cfg.Database.MigrationsDir)
+	if err != nil {
+		slog.Error("migration failed; database operations may fail", "error", err)
+	}
+	checker.MigrationsDone(err)
+
+	auditRepo := repository.NewAuditRepository(db)
+	broker := pubsub.NewPostgresBroker(db, cfg.Database.URL)
 
 	eventRepo := repository.NewEventRepository(db)
-	eventCtrl := controllers.NewEventController(eventRepo)
+	eventService := service.NewEventService(eventRepo, auditRepo, broker, cfg.Events.Retention)
+	eventCtrl := controllers.NewEventController(eventService)
 
 	availabilityRepo := repository.NewAvailabilityRepository(db)
-	availabilityService := service.NewAvailabilityService(availabilityRepo, eventRepo)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, eventRepo, auditRepo, broker)
 	availabilityCtrl := controllers.NewAvailabilityController(availabilityService)
 
+	workingHoursRepo := repository.NewWorkingHoursRepository(db)
+	workingHoursService := service.NewWorkingHoursService(workingHoursRepo, auditRepo)
+
 	preferredSlotRepo := repository.NewPreferredSlotRepository(db)
-	preferredSlotService := service.NewPreferredSlotService(preferredSlotRepo)
+	preferredSlotService := service.NewPreferredSlotService(preferredSlotRepo, workingHoursRepo, auditRepo)
+
+	blackoutRepo := repository.NewBlackoutRepository(db)
+	blackoutService := service.NewBlackoutService(blackoutRepo, auditRepo)
+
+	freeRangeRepo := repository.NewFreeRangeRepository(db)
+	freeRangeService := service.NewFreeRangeService(freeRangeRepo, eventRepo, blackoutRepo, workingHoursRepo, auditRepo, broker)
+	freeRangeCtrl := controllers.NewFreeRangeController(freeRangeService)
 
-	schedulerService := service.NewSchedulerService(eventRepo, availabilityRepo, preferredSlotRepo)
+	schedulerService := service.NewSchedulerService(eventRepo, availabilityRepo, preferredSlotRepo, blackoutRepo, workingHoursRepo)
 	recommendationCtrl := controllers.NewRecommendationController(schedulerService)
 	preferredSlotCtrl := controllers.NewPreferredSlotController(preferredSlotService)
+	blackoutCtrl := controllers.NewBlackoutController(blackoutService)
+	workingHoursCtrl := controllers.NewWorkingHoursController(workingHoursService)
+	streamCtrl := controllers.NewStreamController(eventService, broker)
 
-	router := gin.Default()
-
-	router.GET("/health", healthCheck)
-
-	events := router.Group("/events")
-	{
-		events.POST("", eventCtrl.CreateEvent)
-		events.GET("", eventCtrl.ListEvents)
-		events.GET("/:id", eventCtrl.GetEvent)
-		events.PUT("/:id", eventCtrl.UpdateEvent)
-		events.DELETE("/:id", eventCtrl.DeleteEvent)
-		events.GET("/:id/recommendations", recommendationCtrl.GetRecommendations)
-
-		availability := events.Group("/:id/availability")
-		{
-			availability.POST("", availabilityCtrl.SubmitAvailability)
-			availability.GET("", availabilityCtrl.GetAvailability)
-			availability.GET("/:participant_id", availabilityCtrl.GetParticipantAvailability)
-			availability.PUT("/:availability_id", availabilityCtrl.UpdateAvailability)
-			availability.DELETE("/:availability_id", availabilityCtrl.DeleteAvailability)
-		}
+	idempotencyStore := newIdempotencyStore(db, cfg.Idempotency)
+	rateLimitStore := newRateLimitStore(db, cfg.RateLimit)
+	rateLimits, bucketIdle := rateLimitRules(cfg.RateLimit, cfg.API.LegacyRoutes)
+
+	metrics.RegisterDB(db)
+
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.Use(middleware.RequestID())
+	engine.Use(middleware.Tracing())
+	engine.Use(middleware.AccessLog())
+	engine.Use(middleware.Metrics())
+	if cfg.RateLimit.Enabled {
+		engine.Use(middleware.RateLimit(rateLimitStore, rateLimits))
+	}
+
+	router.Register(engine, router.Handlers{
+		Events:          eventCtrl,
+		Availability:    availabilityCtrl,
+		FreeRanges:      freeRangeCtrl,
+		Recommendations: recommendationCtrl,
+		PreferredSlots:  preferredSlotCtrl,
+		Blackouts:       blackoutCtrl,
+		WorkingHours:    workingHoursCtrl,
+		Stream:          streamCtrl,
+		Health:          health.Health(checker),
+		Livez:           health.Livez(),
+		Readyz:          health.Readyz(checker),
+		Metrics:         gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})),
+	}, router.Legacy{
+		Enabled:      cfg.API.LegacyRoutes,
+		DeprecatedAt: cfg.API.LegacyDeprecatedAt,
+		Sunset:       cfg.API.LegacySunset,
+	}, middleware.Idempotency(idempotencyStore, cfg.Idempotency.TTL, router.APIPrefix))
+
+	// started in dependency order; Stop runs them down in reverse
+	workers := health.NewWorkers(checker)
+	workers.Go("event_purger", func(ctx context.Context) error {
+		service.NewEventPurger(eventService, time.Hour).Run(ctx)
+		return nil
+	})
+	workers.Go("response_closer", func(ctx context.Context) error {
+		service.NewResponseCloser(eventRepo, eventService, schedulerService, auditRepo, broker, service.DeadlinePolicy{
+			Interval:              cfg.Events.DeadlineInterval,
+			ReminderLead:          cfg.Events.ReminderLead,
+			AutoFinalizeThreshold: float64(cfg.Events.AutoFinalizeThreshold),
+		}).Run(ctx)
+		return nil
+	})
+	workers.Go("idempotency_sweeper", func(ctx context.Context) error {
+		sweepIdempotencyKeys(ctx, idempotencyStore, time.Hour)
+		return nil
+	})
+	if cfg.RateLimit.Enabled {
+		workers.Go("rate_limit_sweeper", func(ctx context.Context) error {
+			sweepRateLimitBuckets(ctx, rateLimitStore, bucketIdle)
+			return nil
+		})
 	}
+	workers.Go("stream_listener", broker.Run)
+	workers.Go("recommendation_notifier", func(ctx context.Context) error {
+		service.NewRecommendationNotifier(broker, schedulerService, freeRangeService).Run(ctx)
+		return nil
+	})
 
-	preferredSlots := router.Group("/preferred-slots")
-	{
-		preferredSlots.POST("", preferredSlotCtrl.CreatePreferredSlot)
-		preferredSlots.GET("/email/:email", preferredSlotCtrl.GetPreferredSlotsByEmail)
-		preferredSlots.PUT("/:id", preferredSlotCtrl.UpdatePreferredSlot)
-		preferredSlots.DELETE("/:id", preferredSlotCtrl.DeletePreferredSlot)
+	srv := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           engine,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
+	srv.RegisterOnShutdown(streamCtrl.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("🚀 Server starting...")
-	router.Run()
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "addr", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining")
+	case err := <-serveErr:
+		slog.Error("server stopped", "error", err)
+	}
+	stop()
+
+	shutdown(srv, checker, workers, cfg.Server.DrainDelay, cfg.Server.ShutdownTimeout)
+}
+
+// shutdown fails readiness and keeps serving for drainDelay so the load
+// balancer stops routing here, then lets in-flight requests finish, stops the
+// workers and closes the database, all within timeout.
+func shutdown(srv *http.Server, checker *health.Checker, workers *health.Workers, drainDelay, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	checker.Drain()
+	select {
+	case <-time.After(drainDelay):
+	case <-ctx.Done():
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("server did not drain in time", "error", err)
+	}
+	if err := workers.Stop(ctx); err != nil {
+		slog.Error("background workers did not stop in time", "error", err)
+	}
+	if err := config.CloseDatabase(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+	slog.Info("shutdown complete")
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecatedSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("SetsDeprecationSunsetAndSuccessor", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Deprecated(
+			time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC),
+			"/v1",
+		))
+		router.GET("/events/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/events/abc", nil))
+
+		assert.Equal(t, "@1792281600", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</v1/events/abc>; rel="successor-version"`, w.Header().Get("Link"))
+	})
+}
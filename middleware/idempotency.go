@@ -0,0 +1,216 @@
+// Package middleware holds gin middleware applied across all routes.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+var (
+	ErrIdempotencyKeyReused     = apperr.New("idempotency_key_reused", http.StatusUnprocessableEntity, "idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = apperr.New("idempotency_key_in_progress", http.StatusConflict, "a request with this idempotency key is still being processed")
+	ErrIdempotencyKeyInvalid    = apperr.New("idempotency_key_invalid", http.StatusBadRequest, "idempotency key must be 1-255 characters")
+)
+
+// Idempotency makes POST, PUT and DELETE requests carrying an Idempotency-Key
+// header safe to retry. The first request's response is stored for ttl and
+// replayed for later requests with the same key and body; reusing the key
+// for a different request is rejected. Server errors and panics are not
+// stored so the client can retry them. Keys are scoped to the caller, so
+// one client can't replay another's response. Requests are matched on the
+// route rather than the raw path, with versionPrefix trimmed, so a retry
+// through an unversioned alias replays the original.
+func Idempotency(store repository.IdempotencyStore, ttl time.Duration, versionPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, ErrIdempotencyKeyInvalid)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, apperr.BadRequest("failed to read request body").Wrap(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now().UTC()
+		record := &model.IdempotencyRecord{
+			Scope:       idempotencyScope(c),
+			Key:         key,
+			Fingerprint: fingerprint(c.Request.Method, route(c, versionPrefix), body),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		ctx := c.Request.Context()
+		existing, err := store.Begin(ctx, record)
+		if err != nil {
+			// the key was released between our insert and lookup
+			if errors.Is(err, apperr.ErrNotFound) {
+				abortWithError(c, ErrIdempotencyKeyInProgress)
+				return
+			}
+			abortWithError(c, err)
+			return
+		}
+		if existing != nil {
+			replay(c, existing, record)
+			return
+		}
+
+		// store the outcome even if the client has gone away meanwhile
+		ctx = context.WithoutCancel(ctx)
+
+		// a panicking handler must not leave the key in progress until it
+		// expires; recovery further out still gets the panic
+		defer func() {
+			if p := recover(); p != nil {
+				release(ctx, store, record)
+				panic(p)
+			}
+		}()
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			release(ctx, store, record)
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.Body = writer.body.Bytes()
+		if err := store.Complete(ctx, record); err != nil {
+			slog.WarnContext(ctx, "failed to store idempotent response", "key", key, "error", err)
+		}
+	}
+}
+
+func release(ctx context.Context, store repository.IdempotencyStore, record *model.IdempotencyRecord) {
+	if err := store.Release(ctx, record.Scope, record.Key); err != nil {
+		slog.WarnContext(ctx, "failed to release idempotency key", "key", record.Key, "error", err)
+	}
+}
+
+func replay(c *gin.Context, existing, record *model.IdempotencyRecord) {
+	if existing.Fingerprint != record.Fingerprint {
+		abortWithError(c, ErrIdempotencyKeyReused)
+		return
+	}
+	if !existing.Completed() {
+		abortWithError(c, ErrIdempotencyKeyInProgress)
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	if existing.ContentType != "" {
+		c.Header("Content-Type", existing.ContentType)
+	}
+	c.Status(existing.StatusCode)
+	if len(existing.Body) > 0 {
+		c.Writer.Write(existing.Body)
+	}
+	c.Abort()
+}
+
+func isMutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodDelete
+}
+
+// idempotencyScope keeps one caller's keys from colliding with another's:
+// the organizer, else the participant token, else the client IP. The token
+// is hashed so it isn't stored.
+func idempotencyScope(c *gin.Context) string {
+	if id, exists := c.Get("user_id"); exists {
+		if userID, ok := id.(uuid.UUID); ok {
+			return "organizer:" + userID.String()
+		}
+	}
+	if token := c.GetHeader(ParticipantTokenHeader); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// route identifies the request's endpoint and resource: the route template
+// without versionPrefix, followed by the path parameters.
+func route(c *gin.Context, versionPrefix string) string {
+	template := c.FullPath()
+	if template == "" {
+		return c.Request.URL.Path
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimPrefix(template, versionPrefix))
+	for _, p := range c.Params {
+		b.WriteString(" " + p.Key + "=" + p.Value)
+	}
+	return b.String()
+}
+
+func fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// captureWriter copies the response body so it can be stored.
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// abortWithError writes err as a problem document, like the controllers do.
+func abortWithError(c *gin.Context, err error) {
+	appErr := apperr.From(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed",
+			"method", c.Request.Method, "route", c.FullPath(), "status", appErr.Status, "error", err)
+	}
+	c.Header("Content-Type", apperr.ProblemContentType)
+	c.Render(appErr.Status, render.JSON{Data: appErr.Problem(c.Request.URL.Path)})
+	c.Abort()
+}
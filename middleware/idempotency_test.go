@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencySuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func() (*gin.Engine, *int) {
+		calls := 0
+		router := gin.New()
+		router.Use(Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, "/v1"))
+		create := func(c *gin.Context) {
+			calls++
+			c.JSON(http.StatusCreated, gin.H{"call": calls})
+		}
+		router.POST("/events", create)
+		router.POST("/v1/events", create)
+		router.POST("/events/:id/restore", create)
+		router.POST("/fail", func(c *gin.Context) {
+			calls++
+			c.JSON(http.StatusInternalServerError, gin.H{"call": calls})
+		})
+		return router, &calls
+	}
+
+	sendFrom := func(router *gin.Engine, addr, path, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.RemoteAddr = addr
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send := func(router *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+		return sendFrom(router, "192.0.2.1:1234", path, key, body)
+	}
+
+	t.Run("Retry_ReplaysOriginalResponse", func(t *testing.T) {
+		router, calls := setup()
+
+		first := send(router, "/events", "key-1", `{"title":"Planning"}`)
+		second := send(router, "/events", "key-1", `{"title":"Planning"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("ReusedKeyWithDifferentBody_Returns422", func(t *testing.T) {
+		router, calls := setup()
+
+		send(router, "/events", "key-1", `{"title":"Planning"}`)
+		w := send(router, "/events", "key-1", `{"title":"Retro"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "idempotency_key_reused")
+	})
+
+	t.Run("ServerError_IsNotStored", func(t *testing.T) {
+		router, calls := setup()
+
+		send(router, "/fail", "key-1", `{}`)
+		w := send(router, "/fail", "key-1", `{}`)
+
+		assert.Equal(t, 2, *calls)
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("Panic_ReleasesKey", func(t *testing.T) {
+		calls := 0
+		router := gin.New()
+		router.Use(gin.Recovery(), Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, "/v1"))
+		router.POST("/panic", func(c *gin.Context) {
+			calls++
+			panic("boom")
+		})
+
+		send(router, "/panic", "key-1", `{}`)
+		w := send(router, "/panic", "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("SameKeyFromAnotherCaller_IsNotReplayed", func(t *testing.T) {
+		router, calls := setup()
+
+		sendFrom(router, "192.0.2.1:1234", "/events", "key-1", `{"title":"Planning"}`)
+		w := sendFrom(router, "198.51.100.7:1234", "/events", "key-1", `{"title":"Planning"}`)
+
+		assert.Equal(t, 2, *calls)
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("RetryThroughLegacyAlias_ReplaysOriginalResponse", func(t *testing.T) {
+		router, calls := setup()
+
+		first := send(router, "/v1/events", "key-1", `{"title":"Planning"}`)
+		second := send(router, "/events", "key-1", `{"title":"Planning"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("ReusedKeyForAnotherResource_Returns422", func(t *testing.T) {
+		router, calls := setup()
+
+		send(router, "/events/a/restore", "key-1", `{}`)
+		w := send(router, "/events/b/restore", "key-1", `{}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("NoKey_RunsEveryTime", func(t *testing.T) {
+		router, calls := setup()
+
+		send(router, "/events", "", `{"title":"Planning"}`)
+		send(router, "/events", "", `{"title":"Planning"}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/ram-ks/meeting-service/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("LabelsByRouteTemplate", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Metrics())
+		router.GET("/events/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		for _, path := range []string{"/events/a", "/events/b", "/nowhere"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}
+
+		assert.Equal(t, 2.0, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", "/events/:id", "200")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+	})
+}
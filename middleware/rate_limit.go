@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+)
+
+const (
+	ParticipantTokenHeader   = "X-Participant-Token"
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+)
+
+// RateLimitKey names what a rule counts requests by.
+type RateLimitKey string
+
+const (
+	RateLimitByIP          RateLimitKey = "ip"
+	RateLimitByOrganizer   RateLimitKey = "organizer"
+	RateLimitByParticipant RateLimitKey = "participant"
+)
+
+var ErrRateLimited = apperr.New("rate_limited", http.StatusTooManyRequests, "too many requests; retry after the time in the Retry-After header")
+
+// RateLimitRule limits one route, given as a method and a route template
+// such as POST /events/:id/availability. Rules with the same Bucket and Key
+// share buckets, so a route served at two paths can be limited as one; an
+// empty Bucket means the method and route.
+type RateLimitRule struct {
+	Method string
+	Route  string
+	Bucket string
+	Key    RateLimitKey
+	Limit  model.RateLimit
+}
+
+func (r RateLimitRule) bucket() string {
+	if r.Bucket != "" {
+		return r.Bucket
+	}
+	return r.Method + " " + r.Route
+}
+
+// RateLimit applies token-bucket rules per route. A route may have several
+// rules, say per participant and per IP, and a request must pass all of
+// them; a rejected request takes no tokens. Rejected requests get 429 with
+// Retry-After. If the store fails the request is let through; limiting is
+// protection, not correctness.
+func RateLimit(store repository.RateLimitStore, rules []RateLimitRule) gin.HandlerFunc {
+	byRoute := make(map[string][]RateLimitRule)
+	for _, rule := range rules {
+		route := rule.Method + " " + rule.Route
+		byRoute[route] = append(byRoute[route], rule)
+	}
+
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		rules := byRoute[route]
+		if len(rules) == 0 {
+			c.Next()
+			return
+		}
+
+		buckets := make([]model.RateLimitBucket, len(rules))
+		for i, rule := range rules {
+			buckets[i] = model.RateLimitBucket{
+				Key:   rule.bucket() + "|" + string(rule.Key) + ":" + hashIdentity(rateLimitIdentity(c, rule.Key)),
+				Limit: rule.Limit,
+			}
+		}
+		decisions, err := store.Take(c.Request.Context(), buckets, time.Now().UTC())
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limit check failed, allowing request", "route", route, "error", err)
+			c.Next()
+			return
+		}
+
+		var tightest *model.RateLimitDecision
+		var retryAfter time.Duration
+		for i, decision := range decisions {
+			if !decision.Allowed && decision.RetryAfter > retryAfter {
+				retryAfter = decision.RetryAfter
+			}
+			if tightest == nil || decision.Remaining < tightest.Remaining {
+				tightest = &decisions[i]
+			}
+		}
+
+		if tightest != nil {
+			c.Header(RateLimitLimitHeader, strconv.Itoa(tightest.Limit))
+			c.Header(RateLimitRemainingHeader, strconv.Itoa(tightest.Remaining))
+		}
+		if retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			abortWithError(c, ErrRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitIdentity finds who a request counts against. Callers without
+// the identity the rule asks for are counted by IP.
+//
+// Participant tokens aren't verified, so a caller can pick a fresh one per
+// request and a participant rule is advisory: it keeps participants behind a
+// shared address from using up each other's allowance, and routes it guards
+// should have an IP rule as well to bound what one address can send.
+func rateLimitIdentity(c *gin.Context, key RateLimitKey) string {
+	switch key {
+	case RateLimitByOrganizer:
+		if id, exists := c.Get("user_id"); exists {
+			if userID, ok := id.(uuid.UUID); ok {
+				return "organizer:" + userID.String()
+			}
+		}
+	case RateLimitByParticipant:
+		if token := c.GetHeader(ParticipantTokenHeader); token != "" {
+			return "token:" + token
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// hashIdentity keeps tokens out of the bucket keys.
+func hashIdentity(identity string) string {
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, []model.RateLimitBucket, time.Time) ([]model.RateLimitDecision, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingRateLimitStore) DeleteIdle(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(store repository.RateLimitStore, rules ...RateLimitRule) (*gin.Engine, *string) {
+		var seenBody string
+		router := gin.New()
+		router.Use(RateLimit(store, rules))
+		router.POST("/events/:id/availability", func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			seenBody = string(body)
+			c.Status(http.StatusCreated)
+		})
+		router.GET("/events/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router, &seenBody
+	}
+
+	submit := func(router *gin.Engine, ip, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/events/1/availability", bytes.NewBufferString(`{"participant_id":"p1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set(ParticipantTokenHeader, token)
+		}
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	perParticipant := RateLimitRule{
+		Method: "POST",
+		Route:  "/events/:id/availability",
+		Key:    RateLimitByParticipant,
+		Limit:  model.RateLimit{Requests: 2, Period: time.Hour},
+	}
+
+	t.Run("RejectsOnceBucketIsEmpty", func(t *testing.T) {
+		router, _ := setup(repository.NewMemoryRateLimitStore(), perParticipant)
+		first := submit(router, "10.0.0.1", "t1")
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, "2", first.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", first.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.1", "t1").Code)
+
+		w := submit(router, "10.0.0.1", "t1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		// a token comes back every 30 minutes
+		assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"code":"rate_limited"`)
+	})
+
+	t.Run("CountsParticipantsSeparately", func(t *testing.T) {
+		router, seenBody := setup(repository.NewMemoryRateLimitStore(), perParticipant)
+
+		submit(router, "10.0.0.1", "t1")
+		submit(router, "10.0.0.1", "t1")
+
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.1", "t2").Code)
+		assert.Equal(t, `{"participant_id":"p1"}`, *seenBody)
+	})
+
+	t.Run("ParticipantInBodyIsNotAnIdentity", func(t *testing.T) {
+		router, _ := setup(repository.NewMemoryRateLimitStore(), perParticipant)
+		send := func(participantID string) int {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/events/1/availability", bytes.NewBufferString(`{"participant_id":"`+participantID+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "10.0.0.1:1234"
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusCreated, send("p1"))
+		assert.Equal(t, http.StatusCreated, send("p2"))
+		// without a token every participant_id counts against the address
+		assert.Equal(t, http.StatusTooManyRequests, send("p3"))
+	})
+
+	t.Run("EveryRuleMustPass", func(t *testing.T) {
+		perIP := RateLimitRule{
+			Method: "POST",
+			Route:  "/events/:id/availability",
+			Key:    RateLimitByIP,
+			Limit:  model.RateLimit{Requests: 1, Period: time.Minute},
+		}
+		router, _ := setup(repository.NewMemoryRateLimitStore(), perParticipant, perIP)
+
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.1", "t1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, submit(router, "10.0.0.1", "t2").Code)
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.2", "t2").Code)
+	})
+
+	t.Run("RejectedRequestTakesNoTokens", func(t *testing.T) {
+		perIP := RateLimitRule{
+			Method: "POST",
+			Route:  "/events/:id/availability",
+			Key:    RateLimitByIP,
+			Limit:  model.RateLimit{Requests: 1, Period: time.Hour},
+		}
+		router, _ := setup(repository.NewMemoryRateLimitStore(), perParticipant, perIP)
+
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.1", "t1").Code)
+		// turned away by the IP rule, so t2's bucket stays full
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusTooManyRequests, submit(router, "10.0.0.1", "t2").Code)
+		}
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.2", "t2").Code)
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.3", "t2").Code)
+	})
+
+	t.Run("OtherRoutesUnlimited", func(t *testing.T) {
+		router, _ := setup(repository.NewMemoryRateLimitStore(), perParticipant)
+
+		for i := 0; i < 5; i++ {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/events/1", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+		}
+	})
+
+	t.Run("StoreFailureAllowsRequest", func(t *testing.T) {
+		router, _ := setup(failingRateLimitStore{}, perParticipant)
+
+		assert.Equal(t, http.StatusCreated, submit(router, "10.0.0.1", "t1").Code)
+	})
+}
+
+func TestRateLimitBucket(t *testing.T) {
+	limit := model.RateLimit{Requests: 60, Period: time.Minute, Burst: 2}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tokens := float64(limit.Size())
+	d := limit.Check(tokens)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 1, d.Remaining)
+	d = limit.Check(tokens - 2)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, time.Second, d.RetryAfter)
+
+	// refills at one a second but never beyond the burst
+	assert.Equal(t, 1.0, limit.Refill(0, start, start.Add(time.Second)))
+	assert.Equal(t, 2.0, limit.Refill(0, start, start.Add(time.Hour)))
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(seen *string) *gin.Engine {
+		router := gin.New()
+		router.Use(RequestID())
+		router.GET("/", func(c *gin.Context) {
+			*seen = logging.RequestIDFromContext(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	t.Run("PropagatesCallerID", func(t *testing.T) {
+		var seen string
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "abc-123")
+
+		setup(&seen).ServeHTTP(w, req)
+
+		assert.Equal(t, "abc-123", seen)
+		assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("ReplacesInvalidID", func(t *testing.T) {
+		var seen string
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+
+		setup(&seen).ServeHTTP(w, req)
+
+		assert.NotEqual(t, "bad id\nwith newline", seen)
+		assert.Len(t, seen, 36)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	})
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_participants_email_lower;
+DROP INDEX IF EXISTS idx_time_slots_event_range;
+DROP INDEX IF EXISTS idx_events_title_trgm;
+DROP INDEX IF EXISTS idx_events_organizer_updated;
+DROP INDEX IF EXISTS idx_events_organizer_created;
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_events_organizer_created ON events(organizer_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_events_organizer_updated ON events(organizer_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_events_title_trgm ON events USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_time_slots_event_range ON time_slots(event_id, start_time, end_time);
+CREATE INDEX IF NOT EXISTS idx_participants_email_lower ON participants(LOWER(email), event_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_audit_log_immutable ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_immutable();
+DROP INDEX IF EXISTS idx_audit_log_entity;
+DROP INDEX IF EXISTS idx_audit_log_event;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_availability_revisions_event;
+DROP TABLE IF EXISTS availability_revisions;
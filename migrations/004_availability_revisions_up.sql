@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS availability_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    availability_id UUID NOT NULL,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    participant_id UUID NOT NULL REFERENCES participants(id) ON DELETE CASCADE,
+    slot_id UUID NOT NULL REFERENCES time_slots(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    available_from TIMESTAMP WITH TIME ZONE,
+    available_to TIMESTAMP WITH TIME ZONE,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(participant_id, slot_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_revisions_event ON availability_revisions(event_id, participant_id);
+
+-- Seed the current answers as the first revision so later changes have something to diff against
+INSERT INTO availability_revisions (availability_id, event_id, participant_id, slot_id, revision, status, available_from, available_to, created_at)
+SELECT a.id, a.event_id, a.participant_id, a.slot_id, 1, a.status, a.available_from, a.available_to, a.updated_at
+FROM availability a
+WHERE NOT EXISTS (
+    SELECT 1 FROM availability_revisions r WHERE r.participant_id = a.participant_id AND r.slot_id = a.slot_id
+);
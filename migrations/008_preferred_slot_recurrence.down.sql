@@ -0,0 +1,2 @@
+ALTER TABLE preferred_slots DROP COLUMN IF EXISTS exdates;
+ALTER TABLE preferred_slots DROP COLUMN IF EXISTS rrule;
@@ -0,0 +1,2 @@
+ALTER TABLE preferred_slots ADD COLUMN IF NOT EXISTS rrule TEXT;
+ALTER TABLE preferred_slots ADD COLUMN IF NOT EXISTS exdates DATE[] NOT NULL DEFAULT '{}';
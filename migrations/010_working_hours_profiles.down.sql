@@ -0,0 +1 @@
+DROP TABLE IF EXISTS working_hours_profiles;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS working_hours_profiles (
+    email VARCHAR(255) PRIMARY KEY,
+    id UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    timezone VARCHAR(50) NOT NULL,
+    hours JSONB NOT NULL DEFAULT '[]',
+    not_before TIME,
+    not_after TIME,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE preferred_slots DROP COLUMN IF EXISTS level;
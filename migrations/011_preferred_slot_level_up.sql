@@ -0,0 +1,2 @@
+ALTER TABLE preferred_slots ADD COLUMN IF NOT EXISTS level VARCHAR(20) NOT NULL DEFAULT 'ideal'
+    CHECK (level IN ('ideal', 'acceptable', 'avoid'));
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS free_ranges;
+
+ALTER TABLE events DROP COLUMN IF EXISTS search_end;
+ALTER TABLE events DROP COLUMN IF EXISTS search_start;
@@ -0,0 +1,2 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS submission_note;
+ALTER TABLE availability DROP COLUMN IF EXISTS note;
@@ -0,0 +1,3 @@
+ALTER TABLE availability ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+-- The note on a participant's latest submission is theirs, not each answer's
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS submission_note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_events_open_respond_by;
+
+ALTER TABLE events DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE events DROP COLUMN IF EXISTS auto_finalize;
+ALTER TABLE events DROP COLUMN IF EXISTS respond_by;
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+type AuditEntityType string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionPurge   AuditAction = "purge"
+)
+
+const (
+	AuditEntityEvent         AuditEntityType = "event"
+	AuditEntitySlot          AuditEntityType = "slot"
+	AuditEntityParticipant   AuditEntityType = "participant"
+	AuditEntityAvailability  AuditEntityType = "availability"
+	AuditEntityPreferredSlot AuditEntityType = "preferred_slot"
+	AuditEntityBlackout      AuditEntityType = "blackout"
+	AuditEntityWorkingHours  AuditEntityType = "working_hours"
+	AuditEntityFreeRanges    AuditEntityType = "free_ranges"
+)
+
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 200
+)
+
+// AuditEntry is one row of the append-only audit log. EventID is set for
+// entities that belong to an event so its history can be paged in one query.
+type AuditEntry struct {
+	ID         uuid.UUID       `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntityType `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	EventID    *uuid.UUID      `json:"event_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditCursor struct {
+	At time.Time `json:"t"`
+	ID uuid.UUID `json:"id"`
+}
+
+func (c AuditCursor) Encode() string {
+	return encodeCursor(c)
+}
+
+func DecodeAuditCursor(raw string) (*AuditCursor, error) {
+	var c AuditCursor
+	if err := decodeCursor(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor *string      `json:"next_cursor"`
+}
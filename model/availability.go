@@ -12,8 +12,30 @@ const (
 	AvailabilityStatusAvailable   AvailabilityStatus = "available"
 	AvailabilityStatusUnavailable AvailabilityStatus = "unavailable"
 	AvailabilityStatusPartial     AvailabilityStatus = "partial"
+	// AvailabilityStatusIfNeeded is "I can, but I'd rather not": it counts
+	// towards how many can attend but scores the slot lower.
+	AvailabilityStatusIfNeeded AvailabilityStatus = "if_needed"
 )
 
+// AvailabilityStatuses lists every status a participant may submit.
+var AvailabilityStatuses = []AvailabilityStatus{
+	AvailabilityStatusAvailable,
+	AvailabilityStatusUnavailable,
+	AvailabilityStatusPartial,
+	AvailabilityStatusIfNeeded,
+}
+
+func (s AvailabilityStatus) IsValid() bool {
+	for _, status := range AvailabilityStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Availability is a participant's answer for a slot. Note explains this
+// answer and is stored HTML-escaped.
 type Availability struct {
 	ID            uuid.UUID          `json:"id"`
 	EventID       uuid.UUID          `json:"event_id"`
@@ -22,6 +44,70 @@ type Availability struct {
 	Status        AvailabilityStatus `json:"status"`
 	AvailableFrom *time.Time         `json:"available_from,omitempty"`
 	AvailableTo   *time.Time         `json:"available_to,omitempty"`
+	Note          string             `json:"note,omitempty"`
 	CreatedAt     time.Time          `json:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at"`
 }
+
+// SameAnswer reports whether b answers the slot the same way as a. Notes
+// don't count; only the status and window are kept in revisions.
+func (a Availability) SameAnswer(b Availability) bool {
+	return a.Status == b.Status &&
+		sameTime(a.AvailableFrom, b.AvailableFrom) &&
+		sameTime(a.AvailableTo, b.AvailableTo)
+}
+
+// NewRevision records the answer as it now stands; the repository numbers
+// it.
+func (a Availability) NewRevision() *AvailabilityRevision {
+	return &AvailabilityRevision{
+		ID:             uuid.New(),
+		AvailabilityID: a.ID,
+		EventID:        a.EventID,
+		ParticipantID:  a.ParticipantID,
+		SlotID:         a.SlotID,
+		Status:         a.Status,
+		AvailableFrom:  a.AvailableFrom,
+		AvailableTo:    a.AvailableTo,
+		CreatedAt:      a.UpdatedAt,
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// AvailabilityRevision is one recorded state of a participant's answer for a
+// slot. Revisions are numbered per (participant, slot) starting at 1. A
+// Deleted revision records that the answer was removed and repeats its last
+// state.
+type AvailabilityRevision struct {
+	ID             uuid.UUID            `json:"id"`
+	AvailabilityID uuid.UUID            `json:"availability_id"`
+	EventID        uuid.UUID            `json:"event_id"`
+	ParticipantID  uuid.UUID            `json:"participant_id"`
+	SlotID         uuid.UUID            `json:"slot_id"`
+	Revision       int                  `json:"revision"`
+	Status         AvailabilityStatus   `json:"status"`
+	AvailableFrom  *time.Time           `json:"available_from,omitempty"`
+	AvailableTo    *time.Time           `json:"available_to,omitempty"`
+	Deleted        bool                 `json:"deleted,omitempty"`
+	Changes        []AvailabilityChange `json:"changes,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+// AvailabilityChange describes one field that differs from the previous revision.
+type AvailabilityChange struct {
+	Field string  `json:"field"`
+	From  *string `json:"from"`
+	To    *string `json:"to"`
+}
+
+type ParticipantAvailabilityHistory struct {
+	EventID       uuid.UUID              `json:"event_id"`
+	ParticipantID uuid.UUID              `json:"participant_id"`
+	Revisions     []AvailabilityRevision `json:"revisions"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Blackout is a run of whole days, StartDate to EndDate inclusive and local
+// to Timezone, when a person cannot meet at all, such as leave or travel.
+type Blackout struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	Timezone  string    `json:"timezone"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateBlackoutRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	StartDate string `json:"start_date" binding:"required,date"`
+	EndDate   string `json:"end_date" binding:"required,date"`
+	Timezone  string `json:"timezone" binding:"required,timezone"`
+	Reason    string `json:"reason,omitempty" binding:"max=200"`
+}
+
+type UpdateBlackoutRequest struct {
+	StartDate *string `json:"start_date" binding:"omitempty,date"`
+	EndDate   *string `json:"end_date" binding:"omitempty,date"`
+	Timezone  *string `json:"timezone" binding:"omitempty,timezone"`
+	Reason    *string `json:"reason" binding:"omitempty,max=200"`
+}
+
+// BlackoutConflict is a participant a slot falls in a blackout for.
+type BlackoutConflict struct {
+	ParticipantID uuid.UUID `json:"participant_id"`
+	Name          string    `json:"name"`
+	BlackoutID    uuid.UUID `json:"blackout_id"`
+	Reason        string    `json:"reason,omitempty"`
+}
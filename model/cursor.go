@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ram-ks/meeting-service/apperr"
+)
+
+var ErrInvalidCursor = apperr.New("invalid_cursor", http.StatusBadRequest, "invalid cursor")
+
+// Cursors are opaque to clients: base64url-encoded JSON of the keyset position.
+func encodeCursor(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -14,8 +14,29 @@ const (
 	EventStatusOpen      EventStatus = "open"
 	EventStatusFinalized EventStatus = "finalized"
 	EventStatusCancelled EventStatus = "cancelled"
+	// EventStatusClosed is an event past its respond_by deadline: it takes
+	// no more answers but can still be finalized.
+	EventStatusClosed EventStatus = "closed"
 )
 
+// EventStatuses lists every status an event can be in.
+var EventStatuses = []EventStatus{
+	EventStatusDraft,
+	EventStatusOpen,
+	EventStatusClosed,
+	EventStatusFinalized,
+	EventStatusCancelled,
+}
+
+func (s EventStatus) IsValid() bool {
+	for _, status := range EventStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	ParticipantStatusPending   ParticipantStatus = "pending"
 	ParticipantStatusResponded ParticipantStatus = "responded"
@@ -31,15 +52,22 @@ type TimeSlot struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Participant is someone invited to an event. SubmissionNote is the note
+// on their latest availability submission, stored HTML-escaped.
 type Participant struct {
-	ID        uuid.UUID         `json:"id"`
-	EventID   uuid.UUID         `json:"event_id"`
-	Email     string            `json:"email"`
-	Name      string            `json:"name"`
-	Status    ParticipantStatus `json:"status"`
-	CreatedAt time.Time         `json:"created_at"`
+	ID             uuid.UUID         `json:"id"`
+	EventID        uuid.UUID         `json:"event_id"`
+	Email          string            `json:"email"`
+	Name           string            `json:"name"`
+	Status         ParticipantStatus `json:"status"`
+	SubmissionNote string            `json:"submission_note,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
+// Event is a meeting being scheduled. Past RespondBy it takes no more
+// answers, and with AutoFinalize it is then finalized on its best slot if
+// that slot is good enough. RemindedAt is when pending participants were
+// last reminded of the deadline.
 type Event struct {
 	ID              uuid.UUID     `json:"id"`
 	Title           string        `json:"title"`
@@ -48,8 +76,13 @@ type Event struct {
 	Duration        string        `json:"duration"`
 	Status          EventStatus   `json:"status"`
 	FinalizedSlotID *uuid.UUID    `json:"finalized_slot_id,omitempty"`
+	SearchWindow    *SearchWindow `json:"search_window,omitempty"`
+	RespondBy       *time.Time    `json:"respond_by,omitempty"`
+	AutoFinalize    bool          `json:"auto_finalize"`
+	RemindedAt      *time.Time    `json:"reminded_at,omitempty"`
 	CreatedAt       time.Time     `json:"created_at"`
 	UpdatedAt       time.Time     `json:"updated_at"`
+	DeletedAt       *time.Time    `json:"deleted_at,omitempty"`
 	ProposedSlots   []TimeSlot    `json:"proposed_slots,omitempty"`
 	Participants    []Participant `json:"participants,omitempty"`
 }
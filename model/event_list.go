@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultEventPageSize = 20
+	MaxEventPageSize     = 100
+)
+
+type EventSort string
+
+const (
+	EventSortCreatedAsc  EventSort = "created_at"
+	EventSortCreatedDesc EventSort = "-created_at"
+	EventSortUpdatedAsc  EventSort = "updated_at"
+	EventSortUpdatedDesc EventSort = "-updated_at"
+)
+
+// IsValid reports whether s is one of the supported sort options.
+func (s EventSort) IsValid() bool {
+	switch s {
+	case EventSortCreatedAsc, EventSortCreatedDesc, EventSortUpdatedAsc, EventSortUpdatedDesc:
+		return true
+	}
+	return false
+}
+
+// Column returns the events column the sort is keyed on.
+func (s EventSort) Column() string {
+	if s == EventSortUpdatedAsc || s == EventSortUpdatedDesc {
+		return "updated_at"
+	}
+	return "created_at"
+}
+
+// Descending reports whether the sort runs newest first.
+func (s EventSort) Descending() bool {
+	return s == EventSortCreatedDesc || s == EventSortUpdatedDesc
+}
+
+// EventCursor is the keyset position of the last event on a page: the value
+// of the sort column plus the id as a tie-breaker.
+type EventCursor struct {
+	Sort EventSort `json:"s"`
+	At   time.Time `json:"t"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (c EventCursor) Encode() string {
+	return encodeCursor(c)
+}
+
+func DecodeEventCursor(raw string) (*EventCursor, error) {
+	var c EventCursor
+	if err := decodeCursor(raw, &c); err != nil {
+		return nil, err
+	}
+	if !c.Sort.IsValid() || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type EventFilter struct {
+	OrganizerID      uuid.UUID
+	Statuses         []EventStatus
+	SlotsFrom        *time.Time
+	SlotsTo          *time.Time
+	Title            string
+	ParticipantEmail string
+	Sort             EventSort
+	Limit            int
+	Cursor           *EventCursor
+}
+
+type EventPage struct {
+	Events     []Event `json:"events"`
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventCursor_RoundTrip(t *testing.T) {
+	for _, sort := range []EventSort{EventSortCreatedAsc, EventSortCreatedDesc, EventSortUpdatedAsc, EventSortUpdatedDesc} {
+		t.Run(string(sort), func(t *testing.T) {
+			cursor := EventCursor{
+				Sort: sort,
+				At:   time.Date(2026, 3, 1, 9, 30, 0, 123456000, time.UTC),
+				ID:   uuid.New(),
+			}
+
+			decoded, err := DecodeEventCursor(cursor.Encode())
+
+			assert.NoError(t, err)
+			assert.Equal(t, cursor.Sort, decoded.Sort)
+			assert.True(t, cursor.At.Equal(decoded.At))
+			assert.Equal(t, cursor.ID, decoded.ID)
+		})
+	}
+}
+
+func TestDecodeEventCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	valid := EventCursor{Sort: EventSortCreatedDesc, At: time.Now().UTC(), ID: uuid.New()}.Encode()
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"created_at","t":"2026-03-01T09:00:00Z","id":"` + uuid.NewString() + `"}`))},
+		{"not json", encode("created_at|2026-03-01")},
+		{"truncated", valid[:len(valid)/2]},
+		{"tampered", valid[:len(valid)-2] + "$$"},
+		{"unknown sort", encode(`{"s":"title","t":"2026-03-01T09:00:00Z","id":"` + uuid.NewString() + `"}`)},
+		{"missing id", encode(`{"s":"created_at","t":"2026-03-01T09:00:00Z"}`)},
+		{"invalid id", encode(`{"s":"created_at","t":"2026-03-01T09:00:00Z","id":"42"}`)},
+		{"invalid time", encode(`{"s":"created_at","t":"yesterday","id":"` + uuid.NewString() + `"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeEventCursor(tt.raw)
+
+			assert.Nil(t, cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchWindow is the span a flexible event may be scheduled in. Instead of
+// answering proposed slots, participants paint the ranges within it that
+// they are free, and the scheduler finds the windows that suit most of them.
+type SearchWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type SearchWindowRequest struct {
+	Start string `json:"start" binding:"required,rfc3339"`
+	End   string `json:"end" binding:"required,rfc3339"`
+}
+
+// FreeRange is a span within an event's search window that a participant is
+// free for.
+type FreeRange struct {
+	ID            uuid.UUID `json:"id"`
+	EventID       uuid.UUID `json:"event_id"`
+	ParticipantID uuid.UUID `json:"participant_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SubmitFreeRangesRequest replaces all of a participant's ranges for the
+// event; an empty list clears them.
+type SubmitFreeRangesRequest struct {
+	ParticipantID uuid.UUID          `json:"participant_id" binding:"required"`
+	Ranges        []FreeRangeRequest `json:"ranges" binding:"max=200,dive"`
+}
+
+type FreeRangeRequest struct {
+	StartTime string `json:"start_time" binding:"required,rfc3339"`
+	EndTime   string `json:"end_time" binding:"required,rfc3339"`
+}
+
+// FreeWindow is a synthetic recommendation for a flexible event: a span of
+// at least the event's duration, StartTime to EndTime, throughout which the
+// listed participants are all free. Any Duration-long part of it can be
+// promoted to a proposed slot.
+type FreeWindow struct {
+	StartTime           time.Time   `json:"start_time"`
+	EndTime             time.Time   `json:"end_time"`
+	FreeCount           int         `json:"free_count"`
+	TotalParticipants   int         `json:"total_participants"`
+	AvailabilityPercent float64     `json:"availability_percent"`
+	ParticipantIDs      []uuid.UUID `json:"participant_ids"`
+}
+
+// FreeWindowResponse lists every window in which the most participants are
+// free, earliest first.
+type FreeWindowResponse struct {
+	EventID  uuid.UUID    `json:"event_id"`
+	Duration string       `json:"duration"`
+	Windows  []FreeWindow `json:"windows"`
+}
+
+// PromoteWindowRequest turns part of a free window into a proposed slot.
+// The slot must lie inside the search window and be at least the event's
+// duration long.
+type PromoteWindowRequest struct {
+	StartTime string `json:"start_time" binding:"required,timestamp"`
+	EndTime   string `json:"end_time" binding:"required,timestamp"`
+	Timezone  string `json:"timezone" binding:"required,timezone"`
+}
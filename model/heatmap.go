@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Heatmap is the grid of who is free when for an event: a row per proposed
+// slot, in start order, with a status per participant in the order of
+// Participants. A participant who hasn't answered a slot has an empty
+// status there.
+type Heatmap struct {
+	EventID      uuid.UUID            `json:"event_id"`
+	Participants []HeatmapParticipant `json:"participants"`
+	Slots        []HeatmapSlot        `json:"slots"`
+
+	// BucketSize and Buckets are set when a bucket size is asked for.
+	BucketSize string          `json:"bucket_size,omitempty"`
+	Buckets    []HeatmapBucket `json:"buckets,omitempty"`
+}
+
+// HeatmapParticipant is a column of the grid. Coverage is the percentage
+// of slots the participant has answered.
+type HeatmapParticipant struct {
+	ParticipantID uuid.UUID `json:"participant_id"`
+	Name          string    `json:"name"`
+	Answered      int       `json:"answered"`
+	Coverage      float64   `json:"coverage"`
+}
+
+type HeatmapSlot struct {
+	SlotID    uuid.UUID            `json:"slot_id"`
+	StartTime time.Time            `json:"start_time"`
+	EndTime   time.Time            `json:"end_time"`
+	Statuses  []AvailabilityStatus `json:"statuses"`
+	Totals    HeatmapTotals        `json:"totals"`
+}
+
+// HeatmapTotals counts a slot's answers by status.
+type HeatmapTotals struct {
+	Available   int `json:"available"`
+	Partial     int `json:"partial"`
+	IfNeeded    int `json:"if_needed"`
+	Unavailable int `json:"unavailable"`
+	NoResponse  int `json:"no_response"`
+}
+
+// HeatmapBucket is a fixed-length stretch of time across all slots. Free
+// counts participants free for all of it, taking partial answers' windows
+// into account; IfNeeded those who would only come if needed.
+type HeatmapBucket struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Free      int       `json:"free"`
+	IfNeeded  int       `json:"if_needed"`
+	Total     int       `json:"total"`
+}
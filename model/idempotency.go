@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a mutating request sent with an
+// Idempotency-Key header so that retries get the original response. A record
+// with a zero StatusCode is still in progress.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	Method      string
+	Path        string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -4,30 +4,130 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
 )
 
+// PreferenceLevel is how much a person wants, or doesn't want, to meet in a
+// preferred slot.
+type PreferenceLevel string
+
+const (
+	PreferenceIdeal      PreferenceLevel = "ideal"
+	PreferenceAcceptable PreferenceLevel = "acceptable"
+	// PreferenceAvoid marks a window the person would rather not meet in;
+	// it outweighs any other preference they have for the same slot.
+	PreferenceAvoid PreferenceLevel = "avoid"
+)
+
+func (l PreferenceLevel) IsValid() bool {
+	switch l {
+	case PreferenceIdeal, PreferenceAcceptable, PreferenceAvoid:
+		return true
+	}
+	return false
+}
+
+// Weight is the level's contribution to a slot's preference score. An
+// unset level is ideal, which is what every slot was before levels.
+func (l PreferenceLevel) Weight() float64 {
+	switch l {
+	case PreferenceAcceptable:
+		return 0.5
+	case PreferenceAvoid:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// PreferredSlot is a window a person prefers to meet in. Without RRule it
+// is StartTime to EndTime, narrowed to DayOfWeek when set. With RRule, an
+// RFC 5545 recurrence rule such as FREQ=WEEKLY;BYDAY=MO,WE,FR, StartTime
+// and EndTime are the first occurrence and every later one keeps their
+// local time of day in Timezone; occurrences falling on an ExDates date
+// (local, YYYY-MM-DD) are skipped.
 type PreferredSlot struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	Timezone  string    `json:"timezone"`
-	DayOfWeek *int      `json:"day_of_week,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID       `json:"id"`
+	Email     string          `json:"email"`
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	Timezone  string          `json:"timezone"`
+	DayOfWeek *int            `json:"day_of_week,omitempty"`
+	RRule     *string         `json:"rrule,omitempty"`
+	ExDates   []string        `json:"exdates,omitempty"`
+	Level     PreferenceLevel `json:"level"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (p *PreferredSlot) IsRecurring() bool {
+	return p.RRule != nil
 }
 
 type CreatePreferredSlotRequest struct {
-	Email     string `json:"email" binding:"required,email"`
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Timezone  string `json:"timezone" binding:"required"`
-	DayOfWeek *int   `json:"day_of_week,omitempty"`
+	Email     string          `json:"email" binding:"required,email"`
+	StartTime string          `json:"start_time" binding:"required,timestamp"`
+	EndTime   string          `json:"end_time" binding:"required,timestamp"`
+	Timezone  string          `json:"timezone" binding:"required,timezone"`
+	DayOfWeek *int            `json:"day_of_week,omitempty" binding:"omitempty,min=0,max=6"`
+	RRule     *string         `json:"rrule,omitempty" binding:"omitempty,max=500"`
+	ExDates   []string        `json:"exdates,omitempty" binding:"omitempty,max=366,dive,date"`
+	Level     PreferenceLevel `json:"level,omitempty" binding:"omitempty,oneof=ideal acceptable avoid"`
 }
 
+// UpdatePreferredSlotRequest changes the given fields. An empty rrule stops
+// the slot recurring; exdates replaces the whole list.
 type UpdatePreferredSlotRequest struct {
-	StartTime *string `json:"start_time"`
-	EndTime   *string `json:"end_time"`
-	Timezone  *string `json:"timezone"`
-	DayOfWeek *int    `json:"day_of_week,omitempty"`
+	StartTime *string          `json:"start_time" binding:"omitempty,timestamp"`
+	EndTime   *string          `json:"end_time" binding:"omitempty,timestamp"`
+	Timezone  *string          `json:"timezone" binding:"omitempty,timezone"`
+	DayOfWeek *int             `json:"day_of_week,omitempty" binding:"omitempty,min=0,max=6"`
+	RRule     *string          `json:"rrule,omitempty" binding:"omitempty,max=500"`
+	ExDates   *[]string        `json:"exdates,omitempty" binding:"omitempty,max=366,dive,date"`
+	Level     *PreferenceLevel `json:"level,omitempty" binding:"omitempty,oneof=ideal acceptable avoid"`
+}
+
+// PreferredSlotRow is the flat form of a preferred slot used by bulk import
+// and export: a window of local time, Start to End (HH:MM) in Timezone, on
+// the given Days. Days are comma-separated RRULE codes such as MO,WE,FR; an
+// empty Days means every day, and an empty Level means ideal.
+type PreferredSlotRow struct {
+	Email    string `json:"email"`
+	Days     string `json:"days"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	Level    string `json:"level,omitempty"`
+}
+
+// PreferredSlotRowColumns are the CSV columns, in export order.
+var PreferredSlotRowColumns = []string{"email", "days", "start", "end", "timezone", "level"}
+
+// ImportMode says what an import does when some rows are invalid.
+type ImportMode string
+
+const (
+	// ImportModeAtomic imports nothing unless every row is valid.
+	ImportModeAtomic ImportMode = "atomic"
+	// ImportModeBestEffort imports the valid rows and reports the rest.
+	ImportModeBestEffort ImportMode = "best_effort"
+)
+
+func (m ImportMode) IsValid() bool {
+	return m == ImportModeAtomic || m == ImportModeBestEffort
+}
+
+// ImportRowError is what was wrong with one row, counted from 0 and, for
+// CSV, not counting the header.
+type ImportRowError struct {
+	Row    int                 `json:"row"`
+	Errors []apperr.FieldError `json:"errors"`
+}
+
+type PreferredSlotImportResult struct {
+	Mode           ImportMode       `json:"mode"`
+	Imported       int              `json:"imported"`
+	Failed         int              `json:"failed"`
+	Errors         []ImportRowError `json:"errors"`
+	PreferredSlots []PreferredSlot  `json:"preferred_slots"`
 }
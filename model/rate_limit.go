@@ -0,0 +1,64 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit is a token bucket: it holds up to Burst tokens, refills at
+// Requests per Period and each request takes one. A zero Burst means
+// Requests.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// RateLimitBucket is one bucket a request takes a token from.
+type RateLimitBucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+type RateLimitDecision struct {
+	Allowed bool
+	// Limit is the bucket size and Remaining the whole tokens left in it.
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token when Allowed is false.
+	RetryAfter time.Duration
+}
+
+func (l RateLimit) Size() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// perSecond is the refill rate.
+func (l RateLimit) perSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Refill returns what a bucket last updated at updated holding tokens holds
+// by now. A new bucket starts full: pass Size() tokens.
+func (l RateLimit) Refill(tokens float64, updated, now time.Time) float64 {
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(l.Size()), tokens+elapsed*l.perSecond())
+	}
+	return tokens
+}
+
+// Check decides whether a token can be taken from a refilled bucket holding
+// tokens, without taking it.
+func (l RateLimit) Check(tokens float64) RateLimitDecision {
+	decision := RateLimitDecision{Limit: l.Size()}
+	if tokens >= 1 {
+		decision.Allowed = true
+		decision.Remaining = int(tokens - 1)
+		return decision
+	}
+	decision.RetryAfter = time.Duration((1 - tokens) / l.perSecond() * float64(time.Second))
+	return decision
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Recommendation struct {
 	SlotID              uuid.UUID `json:"slot_id"`
@@ -8,13 +12,61 @@ type Recommendation struct {
 	AvailableCount      int       `json:"available_count"`
 	TotalParticipants   int       `json:"total_participants"`
 	AvailabilityPercent float64   `json:"availability_percent"`
-	PreferredCount      int       `json:"preferred_count"`
-	PreferredPercent    float64   `json:"preferred_percent"`
 	IsPerfectMatch      bool      `json:"is_perfect_match"`
+
+	// IfNeededCount is how many of AvailableCount answered if_needed. They
+	// count as able to attend but only for half in AvailabilityPercent,
+	// and rule out a perfect match.
+	IfNeededCount int `json:"if_needed_count"`
+
+	// PreferredCount is how many participants find the slot ideal or
+	// acceptable and AvoidCount how many would rather avoid it.
+	// PreferenceScore weighs each participant's level, ideal 1, acceptable
+	// 0.5, avoid -1 and none 0, as a percentage of all participants, so it
+	// runs from -100 to 100. PreferredPercent is PreferredCount as a
+	// percentage of all participants, kept for clients that predate the
+	// score.
+	PreferredCount   int     `json:"preferred_count"`
+	PreferredPercent float64 `json:"preferred_percent"`
+	AvoidCount       int     `json:"avoid_count"`
+	PreferenceScore  float64 `json:"preference_score"`
+
+	// Blackouts lists participants counted unavailable because the slot
+	// falls in one of their blackouts, whatever they answered.
+	Blackouts []BlackoutConflict `json:"blackouts,omitempty"`
+
+	// OutsideHoursCount is how many participants the slot falls outside the
+	// working hours of; it ranks the slot lower and rules out a perfect
+	// match. HoursConflicts lists them, along with anyone whose hard limits
+	// the slot crosses.
+	OutsideHoursCount int             `json:"outside_hours_count"`
+	HoursConflicts    []HoursConflict `json:"hours_conflicts,omitempty"`
+
+	Explain []ParticipantExplanation `json:"explain,omitempty"`
 }
 
 type RecommendationResponse struct {
 	EventID      uuid.UUID        `json:"event_id"`
 	PerfectSlots []Recommendation `json:"perfect_slots"`
 	BestMatches  []Recommendation `json:"best_matches"`
+	// Rejected are slots crossing a participant's hard limits.
+	Rejected []Recommendation `json:"rejected"`
+}
+
+// ParticipantExplanation is one participant's contribution to a slot's score.
+type ParticipantExplanation struct {
+	ParticipantID   uuid.UUID          `json:"participant_id"`
+	Name            string             `json:"name"`
+	Status          AvailabilityStatus `json:"status,omitempty"`
+	Responded       bool               `json:"responded"`
+	Preferred       bool               `json:"preferred"`
+	Preference      PreferenceLevel    `json:"preference,omitempty"`
+	BlackedOut      bool               `json:"blacked_out"`
+	BlackoutReason  string             `json:"blackout_reason,omitempty"`
+	OutsideHours    HoursViolation     `json:"outside_hours,omitempty"`
+	RecentlyChanged bool               `json:"recently_changed"`
+	PreviousStatus  AvailabilityStatus `json:"previous_status,omitempty"`
+	ChangedAt       *time.Time         `json:"changed_at,omitempty"`
+	Note            string             `json:"note,omitempty"`
+	SubmissionNote  string             `json:"submission_note,omitempty"`
 }
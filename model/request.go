@@ -8,14 +8,19 @@ type CreateEventRequest struct {
 	Title         string                     `json:"title" binding:"required"`
 	Description   string                     `json:"description"`
 	Duration      string                     `json:"duration" binding:"required"`
-	ProposedSlots []CreateSlotRequest        `json:"proposed_slots" binding:"required,min=1"`
-	Participants  []CreateParticipantRequest `json:"participants" binding:"required,min=1"`
+	ProposedSlots []CreateSlotRequest        `json:"proposed_slots" binding:"required_without=SearchWindow,dive"`
+	Participants  []CreateParticipantRequest `json:"participants" binding:"required,min=1,dive"`
+	// SearchWindow makes the event flexible: participants paint free
+	// ranges within it, and proposed slots may be left out.
+	SearchWindow *SearchWindowRequest `json:"search_window,omitempty"`
+	RespondBy    string               `json:"respond_by,omitempty" binding:"omitempty,rfc3339"`
+	AutoFinalize bool                 `json:"auto_finalize"`
 }
 
 type CreateSlotRequest struct {
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Timezone  string `json:"timezone" binding:"required"`
+	StartTime string `json:"start_time" binding:"required,timestamp"`
+	EndTime   string `json:"end_time" binding:"required,timestamp"`
+	Timezone  string `json:"timezone" binding:"required,timezone"`
 }
 
 type CreateParticipantRequest struct {
@@ -23,42 +28,68 @@ type CreateParticipantRequest struct {
 	Name  string `json:"name" binding:"required"`
 }
 
+// UpdateEventRequest changes the fields given. An empty respond_by removes
+// the deadline; a later one reopens a closed event.
 type UpdateEventRequest struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Duration    *string `json:"duration"`
+	Title        *string              `json:"title"`
+	Description  *string              `json:"description"`
+	Duration     *string              `json:"duration"`
+	SearchWindow *SearchWindowRequest `json:"search_window"`
+	RespondBy    *string              `json:"respond_by" binding:"omitempty,rfc3339"`
+	AutoFinalize *bool                `json:"auto_finalize"`
 }
 
 type AddSlotRequest struct {
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Timezone  string `json:"timezone" binding:"required"`
+	StartTime string `json:"start_time" binding:"required,timestamp"`
+	EndTime   string `json:"end_time" binding:"required,timestamp"`
+	Timezone  string `json:"timezone" binding:"required,timezone"`
 }
 
 type UpdateSlotRequest struct {
-	StartTime *string `json:"start_time"`
-	EndTime   *string `json:"end_time"`
-	Timezone  *string `json:"timezone"`
+	StartTime *string `json:"start_time" binding:"omitempty,timestamp"`
+	EndTime   *string `json:"end_time" binding:"omitempty,timestamp"`
+	Timezone  *string `json:"timezone" binding:"omitempty,timezone"`
 }
 
 type SubmitAvailabilityRequest struct {
 	ParticipantID uuid.UUID                 `json:"participant_id" binding:"required"`
-	Slots         []SlotAvailabilityRequest `json:"slots" binding:"required,min=1"`
+	Slots         []SlotAvailabilityRequest `json:"slots" binding:"required,min=1,dive"`
+	Note          string                    `json:"note,omitempty" binding:"max=1000"`
 }
 
 type SlotAvailabilityRequest struct {
 	SlotID        uuid.UUID          `json:"slot_id" binding:"required"`
-	Status        AvailabilityStatus `json:"status" binding:"required"`
-	AvailableFrom *string            `json:"available_from,omitempty"`
-	AvailableTo   *string            `json:"available_to,omitempty"`
+	Status        AvailabilityStatus `json:"status" binding:"required,availability_status"`
+	AvailableFrom *string            `json:"available_from,omitempty" binding:"omitempty,rfc3339"`
+	AvailableTo   *string            `json:"available_to,omitempty" binding:"omitempty,rfc3339"`
+	Note          string             `json:"note,omitempty" binding:"max=500"`
 }
 
+// UpdateAvailabilityRequest replaces an answer. A nil Note keeps the
+// current one; an empty one clears it.
 type UpdateAvailabilityRequest struct {
-	Status        AvailabilityStatus `json:"status" binding:"required"`
-	AvailableFrom *string            `json:"available_from,omitempty"`
-	AvailableTo   *string            `json:"available_to,omitempty"`
+	Status        AvailabilityStatus `json:"status" binding:"required,availability_status"`
+	AvailableFrom *string            `json:"available_from,omitempty" binding:"omitempty,rfc3339"`
+	AvailableTo   *string            `json:"available_to,omitempty" binding:"omitempty,rfc3339"`
+	Note          *string            `json:"note,omitempty" binding:"omitempty,max=500"`
 }
 
 type FinalizeEventRequest struct {
 	SlotID uuid.UUID `json:"slot_id" binding:"required"`
 }
+
+type ListEventsRequest struct {
+	Status           []EventStatus `form:"status" binding:"dive,event_status"`
+	From             string        `form:"from"`
+	To               string        `form:"to"`
+	Query            string        `form:"q"`
+	ParticipantEmail string        `form:"participant_email"`
+	Sort             EventSort     `form:"sort"`
+	Limit            int           `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor           string        `form:"cursor"`
+}
+
+type ListHistoryRequest struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor string `form:"cursor"`
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StreamEventType string
+
+const (
+	StreamAvailabilitySubmitted    StreamEventType = "availability.submitted"
+	StreamAvailabilityUpdated      StreamEventType = "availability.updated"
+	StreamAvailabilityDeleted      StreamEventType = "availability.deleted"
+	StreamFreeRangesSubmitted      StreamEventType = "free_ranges.submitted"
+	StreamFreeWindowsUpdated       StreamEventType = "free_windows.updated"
+	StreamParticipantStatusChanged StreamEventType = "participant.status_changed"
+	StreamEventFinalized           StreamEventType = "event.finalized"
+	StreamRecommendationsUpdated   StreamEventType = "recommendations.updated"
+	StreamResponsesClosed          StreamEventType = "event.responses_closed"
+	StreamResponseReminder         StreamEventType = "participant.reminder"
+)
+
+// StreamMessage is one update pushed to an event's subscribers. Origin names
+// the instance that published it so fan-out between instances can skip
+// messages it already delivered locally. Trace carries the publisher's W3C
+// trace context so work triggered by the message joins the same trace.
+type StreamMessage struct {
+	Type    StreamEventType   `json:"type"`
+	EventID uuid.UUID         `json:"event_id"`
+	Data    json.RawMessage   `json:"data"`
+	At      time.Time         `json:"at"`
+	Origin  string            `json:"origin,omitempty"`
+	Trace   map[string]string `json:"trace,omitempty"`
+}
+
+// AvailabilitySubmittedData is also sent when a single answer is updated or
+// deleted, naming its slot.
+type AvailabilitySubmittedData struct {
+	ParticipantID uuid.UUID   `json:"participant_id"`
+	SlotIDs       []uuid.UUID `json:"slot_ids"`
+}
+
+// FreeRangesSubmittedData carries a participant's ranges as stored after
+// merging.
+type FreeRangesSubmittedData struct {
+	ParticipantID uuid.UUID   `json:"participant_id"`
+	Ranges        []FreeRange `json:"ranges"`
+}
+
+type ParticipantStatusChangedData struct {
+	ParticipantID uuid.UUID         `json:"participant_id"`
+	From          ParticipantStatus `json:"from"`
+	To            ParticipantStatus `json:"to"`
+}
+
+type EventFinalizedData struct {
+	SlotID uuid.UUID `json:"slot_id"`
+}
+
+type ResponsesClosedData struct {
+	RespondBy time.Time `json:"respond_by"`
+}
+
+// ResponseReminderData names the participants yet to answer as the
+// deadline nears.
+type ResponseReminderData struct {
+	RespondBy      time.Time   `json:"respond_by"`
+	ParticipantIDs []uuid.UUID `json:"participant_ids"`
+}
+
+// RecommendationSummary is the compact form of a RecommendationResponse sent
+// over the stream.
+type RecommendationSummary struct {
+	PerfectSlotCount int                         `json:"perfect_slot_count"`
+	Top              []RecommendationSummaryItem `json:"top"`
+}
+
+type RecommendationSummaryItem struct {
+	SlotID              uuid.UUID `json:"slot_id"`
+	StartTime           time.Time `json:"start_time"`
+	EndTime             time.Time `json:"end_time"`
+	AvailableCount      int       `json:"available_count"`
+	TotalParticipants   int       `json:"total_participants"`
+	AvailabilityPercent float64   `json:"availability_percent"`
+	IsPerfectMatch      bool      `json:"is_perfect_match"`
+}
+
+// FreeWindowSummary is the compact form of a FreeWindowResponse sent over
+// the stream.
+type FreeWindowSummary struct {
+	WindowCount int          `json:"window_count"`
+	Top         []FreeWindow `json:"top"`
+}
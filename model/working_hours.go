@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkingHoursProfile is when a person works, in their home timezone. Hours
+// are the days and times they would normally meet; NotBefore and NotAfter,
+// when set, are hard limits no meeting may cross on any day. Times of day
+// are HH:MM, local to Timezone.
+type WorkingHoursProfile struct {
+	ID        uuid.UUID      `json:"id"`
+	Email     string         `json:"email"`
+	Timezone  string         `json:"timezone"`
+	Hours     []WorkingHours `json:"hours"`
+	NotBefore *string        `json:"not_before,omitempty"`
+	NotAfter  *string        `json:"not_after,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// WorkingHours is one working stretch on a day of the week (0=Sunday).
+type WorkingHours struct {
+	DayOfWeek int    `json:"day_of_week" binding:"min=0,max=6"`
+	Start     string `json:"start" binding:"required,clock"`
+	End       string `json:"end" binding:"required,clock"`
+}
+
+// PutWorkingHoursRequest replaces a person's profile, creating it if needed.
+type PutWorkingHoursRequest struct {
+	Timezone  string         `json:"timezone" binding:"required,timezone"`
+	Hours     []WorkingHours `json:"hours" binding:"max=21,dive"`
+	NotBefore *string        `json:"not_before,omitempty" binding:"omitempty,clock"`
+	NotAfter  *string        `json:"not_after,omitempty" binding:"omitempty,clock"`
+}
+
+// HoursViolation says how a slot falls outside someone's working hours.
+type HoursViolation string
+
+const (
+	// HoursViolationWorkingHours is outside working hours; the slot is
+	// ranked lower.
+	HoursViolationWorkingHours HoursViolation = "working_hours"
+	// HoursViolationHardLimits crosses not_before or not_after; the slot is
+	// rejected.
+	HoursViolationHardLimits HoursViolation = "hard_limits"
+)
+
+// HoursConflict is a participant a slot falls outside the hours of, with
+// the slot in their local time.
+type HoursConflict struct {
+	ParticipantID uuid.UUID      `json:"participant_id"`
+	Name          string         `json:"name"`
+	Violation     HoursViolation `json:"violation"`
+	Timezone      string         `json:"timezone"`
+	LocalStart    string         `json:"local_start"`
+	LocalEnd      string         `json:"local_end"`
+}
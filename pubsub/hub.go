@@ -0,0 +1,124 @@
+// Package pubsub fans stream messages out to subscribers. The Hub delivers
+// within one process; the Postgres broker relays messages between instances
+// with LISTEN/NOTIFY.
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// subscriptionBuffer is how many messages a slow subscriber may fall behind
+// before further messages to it are dropped.
+const subscriptionBuffer = 32
+
+type Publisher interface {
+	Publish(ctx context.Context, msg model.StreamMessage) error
+}
+
+type Broker interface {
+	Publisher
+	// Subscribe returns a subscription to one event's messages.
+	Subscribe(eventID uuid.UUID) *Subscription
+	// SubscribeAll returns a subscription to every event's messages.
+	SubscribeAll() *Subscription
+}
+
+type Subscription struct {
+	C       <-chan model.StreamMessage
+	ch      chan model.StreamMessage
+	eventID *uuid.UUID
+	hub     *Hub
+	once    sync.Once
+}
+
+// Close stops delivery and closes C.
+func (s *Subscription) Close() {
+	s.once.Do(func() { s.hub.remove(s) })
+}
+
+// Hub is an in-process Broker.
+type Hub struct {
+	mu     sync.RWMutex
+	byID   map[uuid.UUID]map[*Subscription]struct{}
+	global map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		byID:   make(map[uuid.UUID]map[*Subscription]struct{}),
+		global: make(map[*Subscription]struct{}),
+	}
+}
+
+func (h *Hub) Publish(ctx context.Context, msg model.StreamMessage) error {
+	h.Deliver(msg)
+	return nil
+}
+
+func (h *Hub) Subscribe(eventID uuid.UUID) *Subscription {
+	sub := h.newSubscription(&eventID)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.byID[eventID] == nil {
+		h.byID[eventID] = make(map[*Subscription]struct{})
+	}
+	h.byID[eventID][sub] = struct{}{}
+	return sub
+}
+
+func (h *Hub) SubscribeAll() *Subscription {
+	sub := h.newSubscription(nil)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.global[sub] = struct{}{}
+	return sub
+}
+
+// Deliver hands msg to this process's subscribers without blocking; a
+// subscriber whose buffer is full misses the message.
+func (h *Hub) Deliver(msg model.StreamMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.byID[msg.EventID] {
+		send(sub, msg)
+	}
+	for sub := range h.global {
+		send(sub, msg)
+	}
+}
+
+func (h *Hub) newSubscription(eventID *uuid.UUID) *Subscription {
+	ch := make(chan model.StreamMessage, subscriptionBuffer)
+	return &Subscription{C: ch, ch: ch, eventID: eventID, hub: h}
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sub.eventID == nil {
+		delete(h.global, sub)
+	} else if subs := h.byID[*sub.eventID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.byID, *sub.eventID)
+		}
+	}
+	close(sub.ch)
+}
+
+func send(sub *Subscription, msg model.StreamMessage) {
+	select {
+	case sub.ch <- msg:
+	default:
+		slog.Warn("stream subscriber is behind, dropped message", "event_id", msg.EventID, "type", msg.Type)
+	}
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubSuite(t *testing.T) {
+	t.Run("Publish_DeliversToEventAndGlobalSubscribers", func(t *testing.T) {
+		hub := NewHub()
+		eventID := uuid.New()
+
+		sub := hub.Subscribe(eventID)
+		other := hub.Subscribe(uuid.New())
+		all := hub.SubscribeAll()
+		defer sub.Close()
+		defer other.Close()
+		defer all.Close()
+
+		msg := model.StreamMessage{Type: model.StreamEventFinalized, EventID: eventID}
+		assert.NoError(t, hub.Publish(context.Background(), msg))
+
+		assert.Equal(t, msg, <-sub.C)
+		assert.Equal(t, msg, <-all.C)
+		assert.Len(t, other.C, 0)
+	})
+
+	t.Run("Close_StopsDeliveryAndClosesChannel", func(t *testing.T) {
+		hub := NewHub()
+		eventID := uuid.New()
+
+		sub := hub.Subscribe(eventID)
+		sub.Close()
+		sub.Close()
+
+		hub.Deliver(model.StreamMessage{EventID: eventID})
+
+		_, open := <-sub.C
+		assert.False(t, open)
+		assert.Empty(t, hub.byID)
+	})
+
+	t.Run("Deliver_DropsWhenSubscriberIsBehind", func(t *testing.T) {
+		hub := NewHub()
+		eventID := uuid.New()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+
+		for i := 0; i < subscriptionBuffer+5; i++ {
+			hub.Deliver(model.StreamMessage{EventID: eventID})
+		}
+
+		assert.Len(t, sub.C, subscriptionBuffer)
+	})
+}
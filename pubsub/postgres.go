@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// NotifyChannel is the Postgres channel messages are relayed on.
+const NotifyChannel = "meeting_stream"
+
+// PostgresBroker delivers messages locally and relays them to other
+// instances through NOTIFY. Run must be started to receive their messages.
+type PostgresBroker struct {
+	*Hub
+	db       *sql.DB
+	listener *pq.Listener
+	origin   string
+}
+
+func NewPostgresBroker(db *sql.DB, dsn string) *PostgresBroker {
+	b := &PostgresBroker{Hub: NewHub(), db: db, origin: uuid.NewString()}
+	b.listener = pq.NewListener(dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("stream listener connection event", "event", int(event), "error", err)
+		}
+	})
+	return b
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, msg model.StreamMessage) error {
+	b.Deliver(msg)
+
+	msg.Origin = b.origin
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, NotifyChannel, string(payload)); err != nil {
+		return fmt.Errorf("notify %s: %w", msg.Type, err)
+	}
+	return nil
+}
+
+// Run listens for messages from other instances until ctx is cancelled.
+func (b *PostgresBroker) Run(ctx context.Context) error {
+	if err := b.listener.Listen(NotifyChannel); err != nil {
+		return err
+	}
+	defer b.listener.Close()
+
+	// pq drops notifications silently if the connection dies, so check it
+	// now and then
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			go b.listener.Ping()
+		case n := <-b.listener.Notify:
+			// nil after a reconnect; anything sent meanwhile is lost
+			if n == nil {
+				continue
+			}
+			var msg model.StreamMessage
+			if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
+				slog.Warn("dropping malformed stream notification", "error", err)
+				continue
+			}
+			if msg.Origin == b.origin {
+				continue
+			}
+			b.Deliver(msg)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// AuditRepository only appends; entries are never updated or deleted.
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditEntry) error
+	ListByEventID(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	ctx, end := observe(ctx, "audit", "Create")
+	defer end()
+
+	query := `
+		INSERT INTO audit_log (id, actor, action, entity_type, entity_id, event_id, before_data, after_data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID, entry.Actor, entry.Action, entry.EntityType, entry.EntityID, entry.EventID,
+		nullableJSON(entry.Before), nullableJSON(entry.After), entry.CreatedAt,
+	)
+	return err
+}
+
+func (r *auditRepository) ListByEventID(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error) {
+	ctx, end := observe(ctx, "audit", "ListByEventID")
+	defer end()
+
+	if limit <= 0 || limit > model.MaxAuditPageSize {
+		limit = model.DefaultAuditPageSize
+	}
+
+	query := `
+		SELECT id, actor, action, entity_type, entity_id, event_id, before_data, after_data, created_at
+		FROM audit_log WHERE event_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2
+	`
+	args := []interface{}{eventID, limit + 1}
+	if cursor != nil {
+		query = `
+			SELECT id, actor, action, entity_type, entity_id, event_id, before_data, after_data, created_at
+			FROM audit_log WHERE event_id = $1 AND (created_at, id) < ($3, $4)
+			ORDER BY created_at DESC, id DESC LIMIT $2
+		`
+		args = append(args, cursor.At, cursor.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		var before, after []byte
+		err := rows.Scan(
+			&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &e.EventID,
+			&before, &after, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &model.AuditPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		last := page.Entries[limit-1]
+		next := model.AuditCursor{At: last.CreatedAt, ID: last.ID}.Encode()
+		page.NextCursor = &next
+	}
+	return page, nil
+}
+
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
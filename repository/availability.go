@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/ram-ks/meeting-service/model"
@@ -12,11 +11,12 @@ import (
 // Contract, Uppercase mean that it's public
 type AvailabilityRepository interface {
 	Create(ctx context.Context, availability *model.Availability) error
-	Upsert(ctx context.Context, availability *model.Availability) error
+	SaveAnswers(ctx context.Context, participantID uuid.UUID, submissionNote string, answers []*model.Availability) ([]model.Availability, error)
 	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.Availability, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Availability, error)
-	Update(ctx context.Context, availability *model.Availability) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error
+	Delete(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error
+	GetRevisionsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.AvailabilityRevision, error)
 }
 
 // to implement an interface, one needs a type, this is it
@@ -31,39 +31,132 @@ func NewAvailabilityRepository(db *sql.DB) AvailabilityRepository {
 
 // Mehod implementation
 func (r *availabilityRepository) Create(ctx context.Context, availability *model.Availability) error {
+	ctx, end := observe(ctx, "availability", "Create")
+	defer end()
+
 	query := `
-		INSERT INTO availability (id, event_id, participant_id, slot_id, status, available_from, available_to, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO availability (id, event_id, participant_id, slot_id, status, available_from, available_to, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		availability.ID, availability.EventID, availability.ParticipantID, availability.SlotID,
 		availability.Status, availability.AvailableFrom, availability.AvailableTo,
+		availability.Note,
 		availability.CreatedAt, availability.UpdatedAt,
 	)
 	return err
 }
 
-func (r *availabilityRepository) Upsert(ctx context.Context, availability *model.Availability) error {
-	query := `
-		INSERT INTO availability (id, event_id, participant_id, slot_id, status, available_from, available_to, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+// SaveAnswers upserts a participant's answers, appends a revision for each
+// answer that changed and sets the participant's submission note in one
+// transaction, so a submission is stored whole or not at all. It returns the
+// answers the submission replaced.
+func (r *availabilityRepository) SaveAnswers(ctx context.Context, participantID uuid.UUID, submissionNote string, answers []*model.Availability) ([]model.Availability, error) {
+	ctx, end := observe(ctx, "availability", "SaveAnswers")
+	defer end()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockParticipant(ctx, tx, participantID); err != nil {
+		return nil, err
+	}
+
+	// read what the submission replaces under the lock, so a concurrent
+	// submission can't slip in between and leave a stale "before"
+	existing, err := participantAnswers(ctx, tx, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE participants SET submission_note = $1 WHERE id = $2`, submissionNote, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO availability (id, event_id, participant_id, slot_id, status, available_from, available_to, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (participant_id, slot_id) DO UPDATE SET
 			status = EXCLUDED.status,
 			available_from = EXCLUDED.available_from,
 			available_to = EXCLUDED.available_to,
+			note = EXCLUDED.note,
 			updated_at = EXCLUDED.updated_at
-	`
-	_, err := r.db.ExecContext(ctx, query,
-		availability.ID, availability.EventID, availability.ParticipantID, availability.SlotID,
-		availability.Status, availability.AvailableFrom, availability.AvailableTo,
-		availability.CreatedAt, availability.UpdatedAt,
-	)
-	return err
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var replaced []model.Availability
+	for _, a := range answers {
+		prev, existed := existing[a.SlotID]
+		if existed {
+			// the row keeps its original id and created_at on conflict
+			a.ID = prev.ID
+			a.CreatedAt = prev.CreatedAt
+			replaced = append(replaced, prev)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			a.ID, a.EventID, a.ParticipantID, a.SlotID,
+			a.Status, a.AvailableFrom, a.AvailableTo,
+			a.Note,
+			a.CreatedAt, a.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if !existed || !prev.SameAnswer(*a) {
+			if err := createRevision(ctx, tx, a.NewRevision()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return replaced, nil
+}
+
+// participantAnswers loads a participant's current answers keyed by slot.
+func participantAnswers(ctx context.Context, tx *sql.Tx, participantID uuid.UUID) (map[uuid.UUID]model.Availability, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_id, participant_id, slot_id, status, available_from, available_to, note, created_at, updated_at
+		FROM availability WHERE participant_id = $1
+	`, participantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := make(map[uuid.UUID]model.Availability)
+	for rows.Next() {
+		var a model.Availability
+		err := rows.Scan(
+			&a.ID, &a.EventID, &a.ParticipantID, &a.SlotID, &a.Status,
+			&a.AvailableFrom, &a.AvailableTo, &a.Note, &a.CreatedAt, &a.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		answers[a.SlotID] = a
+	}
+	return answers, rows.Err()
 }
 
 func (r *availabilityRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.Availability, error) {
+	ctx, end := observe(ctx, "availability", "GetByEventID")
+	defer end()
+
 	query := `
-		SELECT id, event_id, participant_id, slot_id, status, available_from, available_to, created_at, updated_at
+		SELECT id, event_id, participant_id, slot_id, status, available_from, available_to, note, created_at, updated_at
 		FROM availability WHERE event_id = $1
 	`
 	rows, err := r.db.QueryContext(ctx, query, eventID)
@@ -77,7 +170,7 @@ func (r *availabilityRepository) GetByEventID(ctx context.Context, eventID uuid.
 		var a model.Availability
 		err := rows.Scan(
 			&a.ID, &a.EventID, &a.ParticipantID, &a.SlotID, &a.Status,
-			&a.AvailableFrom, &a.AvailableTo, &a.CreatedAt, &a.UpdatedAt,
+			&a.AvailableFrom, &a.AvailableTo, &a.Note, &a.CreatedAt, &a.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -87,37 +180,141 @@ func (r *availabilityRepository) GetByEventID(ctx context.Context, eventID uuid.
 	return availabilities, nil
 }
 
-func (r *availabilityRepository) Update(ctx context.Context, availability *model.Availability) error {
+// Update saves an answer and, unless revision is nil, appends it as a new
+// revision in the same transaction.
+func (r *availabilityRepository) Update(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error {
+	ctx, end := observe(ctx, "availability", "Update")
+	defer end()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockParticipant(ctx, tx, availability.ParticipantID); err != nil {
+		return err
+	}
+
 	query := `
-		UPDATE availability SET status = $1, available_from = $2, available_to = $3, updated_at = $4
-		WHERE id = $5
+		UPDATE availability SET status = $1, available_from = $2, available_to = $3, note = $4, updated_at = $5
+		WHERE id = $6
 	`
-	availability.UpdatedAt = time.Now().UTC()
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		availability.Status, availability.AvailableFrom, availability.AvailableTo,
-		availability.UpdatedAt, availability.ID,
+		availability.Note, availability.UpdatedAt, availability.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if revision != nil {
+		if err := createRevision(ctx, tx, revision); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *availabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Availability, error) {
+	ctx, end := observe(ctx, "availability", "GetByID")
+	defer end()
+
 	query := `
-		SELECT id, event_id, participant_id, slot_id, status, available_from, available_to, created_at, updated_at
+		SELECT id, event_id, participant_id, slot_id, status, available_from, available_to, note, created_at, updated_at
 		FROM availability WHERE id = $1
 	`
 	a := &model.Availability{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&a.ID, &a.EventID, &a.ParticipantID, &a.SlotID, &a.Status,
-		&a.AvailableFrom, &a.AvailableTo, &a.CreatedAt, &a.UpdatedAt,
+		&a.AvailableFrom, &a.AvailableTo, &a.Note, &a.CreatedAt, &a.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return a, nil
 }
 
-func (r *availabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM availability WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// Delete removes an answer and appends revision, which marks it deleted, in
+// the same transaction.
+func (r *availabilityRepository) Delete(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error {
+	ctx, end := observe(ctx, "availability", "Delete")
+	defer end()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockParticipant(ctx, tx, availability.ParticipantID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM availability WHERE id = $1`, availability.ID); err != nil {
+		return err
+	}
+
+	if err := createRevision(ctx, tx, revision); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockParticipant holds the participant's row until tx ends, so concurrent
+// writes of their answers queue up rather than race for revision numbers.
+func lockParticipant(ctx context.Context, tx *sql.Tx, participantID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM participants WHERE id = $1 FOR UPDATE`, participantID).Scan(&id)
+	return wrapNotFound(err)
+}
+
+// createRevision assigns the next revision number for the participant and
+// slot and stores it back on revision. The caller must hold the
+// participant's lock.
+func createRevision(ctx context.Context, tx *sql.Tx, revision *model.AvailabilityRevision) error {
+	query := `
+		INSERT INTO availability_revisions
+			(id, availability_id, event_id, participant_id, slot_id, revision, status, available_from, available_to, deleted, created_at)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::uuid, $5::uuid, COALESCE(MAX(revision), 0) + 1,
+			$6::varchar, $7::timestamptz, $8::timestamptz, $9::boolean, $10::timestamptz
+		FROM availability_revisions WHERE participant_id = $4::uuid AND slot_id = $5::uuid
+		RETURNING revision
+	`
+	return tx.QueryRowContext(ctx, query,
+		revision.ID, revision.AvailabilityID, revision.EventID, revision.ParticipantID, revision.SlotID,
+		revision.Status, revision.AvailableFrom, revision.AvailableTo, revision.Deleted, revision.CreatedAt,
+	).Scan(&revision.Revision)
+}
+
+func (r *availabilityRepository) GetRevisionsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.AvailabilityRevision, error) {
+	ctx, end := observe(ctx, "availability", "GetRevisionsByEventID")
+	defer end()
+
+	query := `
+		SELECT id, availability_id, event_id, participant_id, slot_id, revision, status, available_from, available_to, deleted, created_at
+		FROM availability_revisions WHERE event_id = $1
+		ORDER BY participant_id, slot_id, revision
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []model.AvailabilityRevision
+	for rows.Next() {
+		var rev model.AvailabilityRevision
+		err := rows.Scan(
+			&rev.ID, &rev.AvailabilityID, &rev.EventID, &rev.ParticipantID, &rev.SlotID, &rev.Revision,
+			&rev.Status, &rev.AvailableFrom, &rev.AvailableTo, &rev.Deleted, &rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
 }
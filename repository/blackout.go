@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+type BlackoutRepository interface {
+	Create(ctx context.Context, blackout *model.Blackout) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Blackout, error)
+	GetByEmail(ctx context.Context, email string) ([]model.Blackout, error)
+	// GetByEmails returns the blackouts of any of emails that may overlap
+	// from-to. Dates are compared with a day's slack either side, since a
+	// blackout's days are local to its timezone; callers check the exact
+	// overlap.
+	GetByEmails(ctx context.Context, emails []string, from, to time.Time) ([]model.Blackout, error)
+	Update(ctx context.Context, blackout *model.Blackout) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type blackoutRepository struct {
+	db *sql.DB
+}
+
+func NewBlackoutRepository(db *sql.DB) BlackoutRepository {
+	return &blackoutRepository{db: db}
+}
+
+// blackoutColumns reads the dates back as text so they round-trip as the
+// YYYY-MM-DD strings the API uses.
+const blackoutColumns = `id, email, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'), timezone, reason, created_at, updated_at`
+
+func (r *blackoutRepository) Create(ctx context.Context, blackout *model.Blackout) error {
+	ctx, end := observe(ctx, "blackout", "Create")
+	defer end()
+
+	query := `
+		INSERT INTO blackouts (id, email, start_date, end_date, timezone, reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		blackout.ID, blackout.Email,
+		blackout.StartDate, blackout.EndDate, blackout.Timezone, blackout.Reason,
+		blackout.CreatedAt, blackout.UpdatedAt,
+	)
+	return err
+}
+
+func (r *blackoutRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Blackout, error) {
+	ctx, end := observe(ctx, "blackout", "GetByID")
+	defer end()
+
+	query := `SELECT ` + blackoutColumns + ` FROM blackouts WHERE id = $1`
+	blackout := &model.Blackout{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&blackout.ID, &blackout.Email,
+		&blackout.StartDate, &blackout.EndDate, &blackout.Timezone, &blackout.Reason,
+		&blackout.CreatedAt, &blackout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+	return blackout, nil
+}
+
+func (r *blackoutRepository) GetByEmail(ctx context.Context, email string) ([]model.Blackout, error) {
+	ctx, end := observe(ctx, "blackout", "GetByEmail")
+	defer end()
+
+	query := `SELECT ` + blackoutColumns + ` FROM blackouts WHERE LOWER(email) = LOWER($1) ORDER BY start_date`
+	rows, err := r.db.QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBlackouts(rows)
+}
+
+func (r *blackoutRepository) GetByEmails(ctx context.Context, emails []string, from, to time.Time) ([]model.Blackout, error) {
+	ctx, end := observe(ctx, "blackout", "GetByEmails")
+	defer end()
+
+	if len(emails) == 0 {
+		return []model.Blackout{}, nil
+	}
+
+	query := `
+		SELECT ` + blackoutColumns + `
+		FROM blackouts
+		WHERE LOWER(email) = ANY($1) AND end_date >= $2::date - 1 AND start_date <= $3::date + 1
+		ORDER BY email, start_date
+	`
+	lowerEmails := make([]string, len(emails))
+	for i, e := range emails {
+		lowerEmails[i] = strings.ToLower(e)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowerEmails),
+		from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBlackouts(rows)
+}
+
+func (r *blackoutRepository) Update(ctx context.Context, blackout *model.Blackout) error {
+	ctx, end := observe(ctx, "blackout", "Update")
+	defer end()
+
+	query := `
+		UPDATE blackouts
+		SET start_date = $1, end_date = $2, timezone = $3, reason = $4, updated_at = $5
+		WHERE id = $6
+	`
+	blackout.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, query,
+		blackout.StartDate, blackout.EndDate, blackout.Timezone, blackout.Reason,
+		blackout.UpdatedAt, blackout.ID,
+	)
+	return err
+}
+
+func (r *blackoutRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "blackout", "Delete")
+	defer end()
+
+	query := `DELETE FROM blackouts WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func scanBlackouts(rows *sql.Rows) ([]model.Blackout, error) {
+	var blackouts []model.Blackout
+	for rows.Next() {
+		var b model.Blackout
+		err := rows.Scan(
+			&b.ID, &b.Email,
+			&b.StartDate, &b.EndDate, &b.Timezone, &b.Reason,
+			&b.CreatedAt, &b.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		blackouts = append(blackouts, b)
+	}
+	return blackouts, rows.Err()
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ram-ks/meeting-service/apperr"
+)
+
+// wrapNotFound turns sql.ErrNoRows into apperr.ErrNotFound so callers can
+// tell a missing row from a failed query with errors.Is.
+func wrapNotFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperr.ErrNotFound.Wrap(err)
+	}
+	return err
+}
@@ -3,18 +3,29 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/ram-ks/meeting-service/model"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type EventRepository interface {
 	Create(ctx context.Context, event *model.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Event, error)
-	List(ctx context.Context, organizerID uuid.UUID) ([]model.Event, error)
+	List(ctx context.Context, filter model.EventFilter) (*model.EventPage, error)
 	Update(ctx context.Context, event *model.Event) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*model.Event, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	PurgeDeleted(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	ListPastDeadline(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	ListDueReminders(ctx context.Context, now, until time.Time) ([]uuid.UUID, error)
+	CloseResponses(ctx context.Context, id uuid.UUID) (bool, error)
+	MarkReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
 	CreateSlot(ctx context.Context, slot *model.TimeSlot) error
 	GetSlotsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.TimeSlot, error)
 	GetSlotByID(ctx context.Context, id uuid.UUID) (*model.TimeSlot, error)
@@ -35,6 +46,9 @@ func NewEventRepository(db *sql.DB) EventRepository {
 }
 
 func (r *eventRepository) Create(ctx context.Context, event *model.Event) error {
+	ctx, end := observe(ctx, "event", "Create")
+	defer end()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -42,12 +56,15 @@ func (r *eventRepository) Create(ctx context.Context, event *model.Event) error
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO events (id, title, description, organizer_id, duration, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO events (id, title, description, organizer_id, duration, status, search_start, search_end,
+			respond_by, auto_finalize, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
+	searchStart, searchEnd := searchBounds(event.SearchWindow)
 	_, err = tx.ExecContext(ctx, query,
 		event.ID, event.Title, event.Description, event.OrganizerID,
-		event.Duration, event.Status, event.CreatedAt, event.UpdatedAt,
+		event.Duration, event.Status, searchStart, searchEnd,
+		event.RespondBy, event.AutoFinalize, event.CreatedAt, event.UpdatedAt,
 	)
 	if err != nil {
 		return err
@@ -84,18 +101,47 @@ func (r *eventRepository) Create(ctx context.Context, event *model.Event) error
 }
 
 func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Event, error) {
+	ctx, end := observe(ctx, "event", "GetByID")
+	defer end()
+
+	return r.getByID(ctx, id, false)
+}
+
+// GetDeletedByID loads an event that has been soft deleted but not yet purged.
+func (r *eventRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*model.Event, error) {
+	ctx, end := observe(ctx, "event", "GetDeletedByID")
+	defer end()
+
+	return r.getByID(ctx, id, true)
+}
+
+func (r *eventRepository) getByID(ctx context.Context, id uuid.UUID, deleted bool) (*model.Event, error) {
 	query := `
-		SELECT id, title, description, organizer_id, duration, status, finalized_slot_id, created_at, updated_at
-		FROM events WHERE id = $1
+		SELECT id, title, description, organizer_id, duration, status, finalized_slot_id, search_start, search_end,
+			respond_by, auto_finalize, reminded_at, created_at, updated_at, deleted_at
+		FROM events WHERE id = $1 AND deleted_at IS NULL
 	`
+	if deleted {
+		query = `
+			SELECT id, title, description, organizer_id, duration, status, finalized_slot_id, search_start, search_end,
+			respond_by, auto_finalize, reminded_at, created_at, updated_at, deleted_at
+			FROM events WHERE id = $1 AND deleted_at IS NOT NULL
+		`
+	}
 	event := &model.Event{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	var searchStart, searchEnd *time.Time
+	queryCtx, span := startQuery(ctx, "SELECT events", attribute.String("db.collection.name", "events"))
+	err := r.db.QueryRowContext(queryCtx, query, id).Scan(
 		&event.ID, &event.Title, &event.Description, &event.OrganizerID,
-		&event.Duration, &event.Status, &event.FinalizedSlotID, &event.CreatedAt, &event.UpdatedAt,
+		&event.Duration, &event.Status, &event.FinalizedSlotID, &searchStart, &searchEnd,
+		&event.RespondBy, &event.AutoFinalize, &event.RemindedAt,
+		&event.CreatedAt, &event.UpdatedAt, &event.DeletedAt,
 	)
+	span.End()
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
+	event.SearchWindow = searchWindow(searchStart, searchEnd)
 
 	slots, err := r.GetSlotsByEventID(ctx, id)
 	if err != nil {
@@ -112,52 +158,281 @@ func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Eve
 	return event, nil
 }
 
-func (r *eventRepository) List(ctx context.Context, organizerID uuid.UUID) ([]model.Event, error) {
-	query := `
-		SELECT id, title, description, organizer_id, duration, status, finalized_slot_id, created_at, updated_at
-		FROM events WHERE organizer_id = $1 ORDER BY created_at DESC
-	`
-	rows, err := r.db.QueryContext(ctx, query, organizerID)
+func (r *eventRepository) List(ctx context.Context, filter model.EventFilter) (*model.EventPage, error) {
+	ctx, end := observe(ctx, "event", "List")
+	defer end()
+
+	query, args, sort, limit := listEventsQuery(filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var events []model.Event
+	events := []model.Event{}
 	for rows.Next() {
 		var event model.Event
+		var searchStart, searchEnd *time.Time
 		err := rows.Scan(
 			&event.ID, &event.Title, &event.Description, &event.OrganizerID,
-			&event.Duration, &event.Status, &event.FinalizedSlotID, &event.CreatedAt, &event.UpdatedAt,
+			&event.Duration, &event.Status, &event.FinalizedSlotID, &searchStart, &searchEnd,
+			&event.RespondBy, &event.AutoFinalize, &event.RemindedAt,
+			&event.CreatedAt, &event.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		event.SearchWindow = searchWindow(searchStart, searchEnd)
 		events = append(events, event)
 	}
-	return events, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &model.EventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		last := page.Events[limit-1]
+		at := last.CreatedAt
+		if sort.Column() == "updated_at" {
+			at = last.UpdatedAt
+		}
+		next := model.EventCursor{Sort: sort, At: at, ID: last.ID}.Encode()
+		page.NextCursor = &next
+	}
+	return page, nil
+}
+
+// listEventsQuery builds List's query from the filter. It returns the
+// query and its arguments along with the sort and page size it settled on;
+// the query fetches one row more than the page to tell if another follows.
+func listEventsQuery(filter model.EventFilter) (string, []interface{}, model.EventSort, int) {
+	sort := filter.Sort
+	if !sort.IsValid() {
+		sort = model.EventSortCreatedDesc
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > model.MaxEventPageSize {
+		limit = model.DefaultEventPageSize
+	}
+
+	args := []interface{}{filter.OrganizerID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conditions := []string{"e.organizer_id = $1", "e.deleted_at IS NULL"}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, st := range filter.Statuses {
+			statuses[i] = string(st)
+		}
+		conditions = append(conditions, "e.status = ANY("+arg(pq.Array(statuses))+")")
+	}
+
+	if filter.SlotsFrom != nil || filter.SlotsTo != nil {
+		slotCond := "ts.event_id = e.id"
+		if filter.SlotsFrom != nil {
+			slotCond += " AND ts.end_time > " + arg(*filter.SlotsFrom)
+		}
+		if filter.SlotsTo != nil {
+			slotCond += " AND ts.start_time < " + arg(*filter.SlotsTo)
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM time_slots ts WHERE "+slotCond+")")
+	}
+
+	if filter.Title != "" {
+		conditions = append(conditions, "e.title ILIKE "+arg("%"+escapeLike(filter.Title)+"%"))
+	}
+
+	if filter.ParticipantEmail != "" {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM participants p WHERE p.event_id = e.id AND LOWER(p.email) = LOWER("+arg(filter.ParticipantEmail)+"))")
+	}
+
+	column := "e." + sort.Column()
+	direction, comparator := "ASC", ">"
+	if sort.Descending() {
+		direction, comparator = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		conditions = append(conditions,
+			fmt.Sprintf("(%s, e.id) %s (%s, %s)", column, comparator, arg(filter.Cursor.At), arg(filter.Cursor.ID)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id, e.title, e.description, e.organizer_id, e.duration, e.status, e.finalized_slot_id,
+		e.search_start, e.search_end, e.respond_by, e.auto_finalize, e.reminded_at, e.created_at, e.updated_at
+		FROM events e WHERE %s ORDER BY %s %s, e.id %s LIMIT %s
+	`, strings.Join(conditions, " AND "), column, direction, direction, arg(limit+1))
+	return query, args, sort, limit
+}
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (r *eventRepository) Update(ctx context.Context, event *model.Event) error {
+	ctx, end := observe(ctx, "event", "Update")
+	defer end()
+
 	query := `
 		UPDATE events SET title = $1, description = $2, duration = $3, status = $4, 
-		finalized_slot_id = $5, updated_at = $6 WHERE id = $7
+		finalized_slot_id = $5, search_start = $6, search_end = $7,
+		respond_by = $8, auto_finalize = $9, reminded_at = $10, updated_at = $11 WHERE id = $12
 	`
 	event.UpdatedAt = time.Now().UTC()
+	searchStart, searchEnd := searchBounds(event.SearchWindow)
 	_, err := r.db.ExecContext(ctx, query,
 		event.Title, event.Description, event.Duration, event.Status,
-		event.FinalizedSlotID, event.UpdatedAt, event.ID,
+		event.FinalizedSlotID, searchStart, searchEnd,
+		event.RespondBy, event.AutoFinalize, event.RemindedAt, event.UpdatedAt, event.ID,
 	)
 	return err
 }
 
+// searchBounds splits an event's search window into its nullable columns.
+func searchBounds(w *model.SearchWindow) (start, end *time.Time) {
+	if w == nil {
+		return nil, nil
+	}
+	return &w.Start, &w.End
+}
+
+func searchWindow(start, end *time.Time) *model.SearchWindow {
+	if start == nil || end == nil {
+		return nil
+	}
+	return &model.SearchWindow{Start: *start, End: *end}
+}
+
+// Delete only marks the event as deleted; slots, participants and availability
+// stay in place until PurgeDeleted removes the event for good.
 func (r *eventRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM events WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	ctx, end := observe(ctx, "event", "Delete")
+	defer end()
+
+	query := `UPDATE events SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
 	return err
 }
 
+func (r *eventRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "event", "Restore")
+	defer end()
+
+	query := `UPDATE events SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
+	return err
+}
+
+// PurgeDeleted hard deletes events soft deleted before the given time; the
+// ON DELETE CASCADE takes their slots, participants and availability with them.
+func (r *eventRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "event", "PurgeDeleted")
+	defer end()
+
+	query := `DELETE FROM events WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id`
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListPastDeadline returns the open events whose respond_by has passed.
+func (r *eventRepository) ListPastDeadline(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "event", "ListPastDeadline")
+	defer end()
+
+	query := `
+		SELECT id FROM events
+		WHERE status = 'open' AND deleted_at IS NULL AND respond_by <= $1
+		ORDER BY respond_by
+	`
+	return r.queryIDs(ctx, query, now)
+}
+
+// ListDueReminders returns the open events whose respond_by falls after now
+// and no later than until, and whose participants haven't been reminded.
+func (r *eventRepository) ListDueReminders(ctx context.Context, now, until time.Time) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "event", "ListDueReminders")
+	defer end()
+
+	query := `
+		SELECT id FROM events
+		WHERE status = 'open' AND deleted_at IS NULL AND reminded_at IS NULL
+		AND respond_by > $1 AND respond_by <= $2
+		ORDER BY respond_by
+	`
+	return r.queryIDs(ctx, query, now, until)
+}
+
+func (r *eventRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CloseResponses moves an open event to closed. It reports false if the
+// event was no longer open, so only one instance acts on a deadline.
+func (r *eventRepository) CloseResponses(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, end := observe(ctx, "event", "CloseResponses")
+	defer end()
+
+	query := `UPDATE events SET status = 'closed', updated_at = $1 WHERE id = $2 AND status = 'open' AND deleted_at IS NULL`
+	return r.execOnce(ctx, query, time.Now().UTC(), id)
+}
+
+// MarkReminded records that an event's participants were reminded, and
+// reports false if they already had been.
+func (r *eventRepository) MarkReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	ctx, end := observe(ctx, "event", "MarkReminded")
+	defer end()
+
+	query := `UPDATE events SET reminded_at = $1 WHERE id = $2 AND reminded_at IS NULL`
+	return r.execOnce(ctx, query, at, id)
+}
+
+func (r *eventRepository) execOnce(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
 func (r *eventRepository) CreateSlot(ctx context.Context, slot *model.TimeSlot) error {
+	ctx, end := observe(ctx, "event", "CreateSlot")
+	defer end()
+
 	query := `
 		INSERT INTO time_slots (id, event_id, start_time, end_time, timezone, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -169,6 +444,9 @@ func (r *eventRepository) CreateSlot(ctx context.Context, slot *model.TimeSlot)
 }
 
 func (r *eventRepository) GetSlotsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.TimeSlot, error) {
+	ctx, end := observe(ctx, "event", "GetSlotsByEventID")
+	defer end()
+
 	query := `
 		SELECT id, event_id, start_time, end_time, timezone, created_at
 		FROM time_slots WHERE event_id = $1 ORDER BY start_time
@@ -192,6 +470,9 @@ func (r *eventRepository) GetSlotsByEventID(ctx context.Context, eventID uuid.UU
 }
 
 func (r *eventRepository) GetSlotByID(ctx context.Context, id uuid.UUID) (*model.TimeSlot, error) {
+	ctx, end := observe(ctx, "event", "GetSlotByID")
+	defer end()
+
 	query := `
 		SELECT id, event_id, start_time, end_time, timezone, created_at
 		FROM time_slots WHERE id = $1
@@ -201,12 +482,15 @@ func (r *eventRepository) GetSlotByID(ctx context.Context, id uuid.UUID) (*model
 		&slot.ID, &slot.EventID, &slot.StartTime, &slot.EndTime, &slot.Timezone, &slot.CreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return slot, nil
 }
 
 func (r *eventRepository) UpdateSlot(ctx context.Context, slot *model.TimeSlot) error {
+	ctx, end := observe(ctx, "event", "UpdateSlot")
+	defer end()
+
 	query := `
 		UPDATE time_slots SET start_time = $1, end_time = $2, timezone = $3 WHERE id = $4
 	`
@@ -215,12 +499,18 @@ func (r *eventRepository) UpdateSlot(ctx context.Context, slot *model.TimeSlot)
 }
 
 func (r *eventRepository) DeleteSlot(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "event", "DeleteSlot")
+	defer end()
+
 	query := `DELETE FROM time_slots WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *eventRepository) CreateParticipant(ctx context.Context, participant *model.Participant) error {
+	ctx, end := observe(ctx, "event", "CreateParticipant")
+	defer end()
+
 	query := `
 		INSERT INTO participants (id, event_id, email, name, status, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -233,8 +523,11 @@ func (r *eventRepository) CreateParticipant(ctx context.Context, participant *mo
 }
 
 func (r *eventRepository) GetParticipantsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.Participant, error) {
+	ctx, end := observe(ctx, "event", "GetParticipantsByEventID")
+	defer end()
+
 	query := `
-		SELECT id, event_id, email, name, status, created_at
+		SELECT id, event_id, email, name, status, submission_note, created_at
 		FROM participants WHERE event_id = $1
 	`
 	rows, err := r.db.QueryContext(ctx, query, eventID)
@@ -246,7 +539,7 @@ func (r *eventRepository) GetParticipantsByEventID(ctx context.Context, eventID
 	var participants []model.Participant
 	for rows.Next() {
 		var p model.Participant
-		err := rows.Scan(&p.ID, &p.EventID, &p.Email, &p.Name, &p.Status, &p.CreatedAt)
+		err := rows.Scan(&p.ID, &p.EventID, &p.Email, &p.Name, &p.Status, &p.SubmissionNote, &p.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -256,21 +549,27 @@ func (r *eventRepository) GetParticipantsByEventID(ctx context.Context, eventID
 }
 
 func (r *eventRepository) GetParticipantByID(ctx context.Context, id uuid.UUID) (*model.Participant, error) {
+	ctx, end := observe(ctx, "event", "GetParticipantByID")
+	defer end()
+
 	query := `
-		SELECT id, event_id, email, name, status, created_at
+		SELECT id, event_id, email, name, status, submission_note, created_at
 		FROM participants WHERE id = $1
 	`
 	p := &model.Participant{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.EventID, &p.Email, &p.Name, &p.Status, &p.CreatedAt,
+		&p.ID, &p.EventID, &p.Email, &p.Name, &p.Status, &p.SubmissionNote, &p.CreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return p, nil
 }
 
 func (r *eventRepository) UpdateParticipantStatus(ctx context.Context, id uuid.UUID, status model.ParticipantStatus) error {
+	ctx, end := observe(ctx, "event", "UpdateParticipantStatus")
+	defer end()
+
 	query := `UPDATE participants SET status = $1 WHERE id = $2`
 	_, err := r.db.ExecContext(ctx, query, status, id)
 	return err
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "standup", "standup"},
+		{"percent", "100% done", `100\% done`},
+		{"underscore", "q3_review", `q3\_review`},
+		{"backslash", `a\b`, `a\\b`},
+		{"escaped wildcard", `\%`, `\\\%`},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeLike(tt.in))
+		})
+	}
+}
+
+func TestListEventsQuery(t *testing.T) {
+	organizerID := uuid.New()
+	cursorID := uuid.New()
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    model.EventFilter
+		wantSort  model.EventSort
+		wantLimit int
+		contains  []string
+		excludes  []string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "defaults",
+			filter:    model.EventFilter{OrganizerID: organizerID},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains: []string{
+				"WHERE e.organizer_id = $1 AND e.deleted_at IS NULL ORDER BY",
+				"ORDER BY e.created_at DESC, e.id DESC LIMIT $2",
+			},
+			excludes: []string{"ILIKE", "time_slots", "participants", "(e.created_at, e.id)"},
+			wantArgs: []interface{}{organizerID, model.DefaultEventPageSize + 1},
+		},
+		{
+			name:      "invalid sort and oversized limit fall back",
+			filter:    model.EventFilter{OrganizerID: organizerID, Sort: "title", Limit: model.MaxEventPageSize + 1},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains:  []string{"ORDER BY e.created_at DESC, e.id DESC LIMIT $2"},
+			wantArgs:  []interface{}{organizerID, model.DefaultEventPageSize + 1},
+		},
+		{
+			name: "statuses",
+			filter: model.EventFilter{
+				OrganizerID: organizerID,
+				Statuses:    []model.EventStatus{model.EventStatusOpen, model.EventStatusClosed},
+				Limit:       10,
+			},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: 10,
+			contains:  []string{"e.status = ANY($2)", "LIMIT $3"},
+			wantArgs:  []interface{}{organizerID, pq.Array([]string{"open", "closed"}), 11},
+		},
+		{
+			name:      "slot range from only",
+			filter:    model.EventFilter{OrganizerID: organizerID, SlotsFrom: &from},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains:  []string{"EXISTS (SELECT 1 FROM time_slots ts WHERE ts.event_id = e.id AND ts.end_time > $2)"},
+			excludes:  []string{"ts.start_time <"},
+			wantArgs:  []interface{}{organizerID, from, model.DefaultEventPageSize + 1},
+		},
+		{
+			name:      "slot range",
+			filter:    model.EventFilter{OrganizerID: organizerID, SlotsFrom: &from, SlotsTo: &to},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains:  []string{"ts.end_time > $2 AND ts.start_time < $3"},
+			wantArgs:  []interface{}{organizerID, from, to, model.DefaultEventPageSize + 1},
+		},
+		{
+			name:      "title and participant email",
+			filter:    model.EventFilter{OrganizerID: organizerID, Title: "50%_off", ParticipantEmail: "Ann@Example.com"},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains: []string{
+				"e.title ILIKE $2",
+				"LOWER(p.email) = LOWER($3)",
+			},
+			wantArgs: []interface{}{organizerID, `%50\%\_off%`, "Ann@Example.com", model.DefaultEventPageSize + 1},
+		},
+		{
+			name: "ascending cursor",
+			filter: model.EventFilter{
+				OrganizerID: organizerID,
+				Sort:        model.EventSortUpdatedAsc,
+				Cursor:      &model.EventCursor{Sort: model.EventSortUpdatedAsc, At: at, ID: cursorID},
+			},
+			wantSort:  model.EventSortUpdatedAsc,
+			wantLimit: model.DefaultEventPageSize,
+			contains: []string{
+				"(e.updated_at, e.id) > ($2, $3)",
+				"ORDER BY e.updated_at ASC, e.id ASC LIMIT $4",
+			},
+			wantArgs: []interface{}{organizerID, at, cursorID, model.DefaultEventPageSize + 1},
+		},
+		{
+			name: "descending cursor",
+			filter: model.EventFilter{
+				OrganizerID: organizerID,
+				Sort:        model.EventSortCreatedDesc,
+				Cursor:      &model.EventCursor{Sort: model.EventSortCreatedDesc, At: at, ID: cursorID},
+			},
+			wantSort:  model.EventSortCreatedDesc,
+			wantLimit: model.DefaultEventPageSize,
+			contains: []string{
+				"(e.created_at, e.id) < ($2, $3)",
+				"ORDER BY e.created_at DESC, e.id DESC LIMIT $4",
+			},
+			wantArgs: []interface{}{organizerID, at, cursorID, model.DefaultEventPageSize + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, sort, limit := listEventsQuery(tt.filter)
+
+			assert.Equal(t, tt.wantSort, sort)
+			assert.Equal(t, tt.wantLimit, limit)
+			for _, fragment := range tt.contains {
+				assert.Contains(t, query, fragment)
+			}
+			for _, fragment := range tt.excludes {
+				assert.NotContains(t, query, fragment)
+			}
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
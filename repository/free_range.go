@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+type FreeRangeRepository interface {
+	// ReplaceForParticipant swaps all of a participant's ranges for an
+	// event for ranges in one transaction.
+	ReplaceForParticipant(ctx context.Context, eventID, participantID uuid.UUID, ranges []model.FreeRange) error
+	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.FreeRange, error)
+}
+
+type freeRangeRepository struct {
+	db *sql.DB
+}
+
+func NewFreeRangeRepository(db *sql.DB) FreeRangeRepository {
+	return &freeRangeRepository{db: db}
+}
+
+func (r *freeRangeRepository) ReplaceForParticipant(ctx context.Context, eventID, participantID uuid.UUID, ranges []model.FreeRange) error {
+	ctx, end := observe(ctx, "free_range", "ReplaceForParticipant")
+	defer end()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM free_ranges WHERE event_id = $1 AND participant_id = $2`, eventID, participantID)
+	if err != nil {
+		return err
+	}
+
+	if len(ranges) > 0 {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO free_ranges (id, event_id, participant_id, start_time, end_time, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, fr := range ranges {
+			_, err := stmt.ExecContext(ctx, fr.ID, eventID, participantID, fr.StartTime, fr.EndTime, fr.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *freeRangeRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.FreeRange, error) {
+	ctx, end := observe(ctx, "free_range", "GetByEventID")
+	defer end()
+
+	query := `
+		SELECT id, event_id, participant_id, start_time, end_time, created_at
+		FROM free_ranges WHERE event_id = $1 ORDER BY participant_id, start_time
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []model.FreeRange
+	for rows.Next() {
+		var fr model.FreeRange
+		err := rows.Scan(&fr.ID, &fr.EventID, &fr.ParticipantID, &fr.StartTime, &fr.EndTime, &fr.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, fr)
+	}
+	return ranges, rows.Err()
+}
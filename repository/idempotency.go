@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// IdempotencyStore keeps the responses of requests sent with an
+// Idempotency-Key. Records are addressed by (scope, key).
+type IdempotencyStore interface {
+	// Begin claims the key for record. If an unexpired record already holds
+	// the key it is returned instead and nothing is written.
+	Begin(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	// Complete stores the response for a claimed key.
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	// Release drops a claimed key so the request can be retried.
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyStore struct {
+	db *sql.DB
+}
+
+func NewIdempotencyStore(db *sql.DB) IdempotencyStore {
+	return &idempotencyStore{db: db}
+}
+
+func (s *idempotencyStore) Begin(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	ctx, end := observe(ctx, "idempotency", "Begin")
+	defer end()
+
+	// An expired record no longer holds the key
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND expires_at <= $3`,
+		record.Scope, record.Key, record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (scope, key, fingerprint, method, path, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (scope, key) DO NOTHING
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		record.Scope, record.Key, record.Fingerprint, record.Method, record.Path,
+		record.CreatedAt, record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 1 {
+		return nil, nil
+	}
+
+	existing := &model.IdempotencyRecord{}
+	err = s.db.QueryRowContext(ctx, `
+		SELECT scope, key, fingerprint, method, path, status_code, content_type, body, created_at, expires_at
+		FROM idempotency_keys WHERE scope = $1 AND key = $2
+	`, record.Scope, record.Key).Scan(
+		&existing.Scope, &existing.Key, &existing.Fingerprint, &existing.Method, &existing.Path,
+		&existing.StatusCode, &existing.ContentType, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+	return existing, nil
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, end := observe(ctx, "idempotency", "Complete")
+	defer end()
+
+	query := `
+		UPDATE idempotency_keys SET status_code = $3, content_type = $4, body = $5
+		WHERE scope = $1 AND key = $2
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		record.Scope, record.Key, record.StatusCode, record.ContentType, record.Body,
+	)
+	return err
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, scope, key string) error {
+	ctx, end := observe(ctx, "idempotency", "Release")
+	defer end()
+
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code = 0`,
+		scope, key,
+	)
+	return err
+}
+
+func (s *idempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, end := observe(ctx, "idempotency", "DeleteExpired")
+	defer end()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// memoryIdempotencyStore keeps records in process. It suits a single
+// instance or tests; use the Postgres store when running several replicas.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]model.IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]model.IdempotencyRecord)}
+}
+
+func memoryKey(scope, key string) string {
+	return scope + "\x00" + key
+}
+
+func (s *memoryIdempotencyStore) Begin(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey(record.Scope, record.Key)
+	if existing, ok := s.records[k]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return &existing, nil
+	}
+	s.records[k] = *record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey(record.Scope, record.Key)
+	existing, ok := s.records[k]
+	if !ok {
+		return nil
+	}
+	existing.StatusCode = record.StatusCode
+	existing.ContentType = record.ContentType
+	existing.Body = append([]byte(nil), record.Body...)
+	s.records[k] = existing
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey(scope, key)
+	if existing, ok := s.records[k]; ok && !existing.Completed() {
+		delete(s.records, k)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for k, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, k)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ram-ks/meeting-service/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ram-ks/meeting-service/repository")
+
+// observe times a repository call for the query duration metric and a debug
+// log line tagged with the caller's request ID, and opens a span for it. Run
+// the call's queries with the returned context and call the returned
+// function when it finishes.
+func observe(ctx context.Context, repository, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := startQuery(ctx, repository+"."+operation,
+		attribute.String("db.operation.name", operation),
+		attribute.String("code.namespace", repository),
+	)
+	return ctx, func() {
+		span.End()
+		elapsed := time.Since(start)
+		metrics.DBQueryDuration.WithLabelValues(repository, operation).Observe(elapsed.Seconds())
+		slog.DebugContext(ctx, "query finished",
+			"repository", repository,
+			"operation", operation,
+			"duration_ms", float64(elapsed.Microseconds())/1000,
+		)
+	}
+}
+
+// startQuery opens a client span for a statement sent to Postgres.
+func startQuery(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system.name", "postgresql"))
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
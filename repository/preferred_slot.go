@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 
 type PreferredSlotRepository interface {
 	Create(ctx context.Context, slot *model.PreferredSlot) error
+	// CreateBatch inserts slots in one transaction; either all are stored
+	// or none are.
+	CreateBatch(ctx context.Context, slots []model.PreferredSlot) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.PreferredSlot, error)
 	GetByEmail(ctx context.Context, email string) ([]model.PreferredSlot, error)
 	GetByEmails(ctx context.Context, emails []string) ([]model.PreferredSlot, error)
@@ -29,38 +33,104 @@ func NewPreferredSlotRepository(db *sql.DB) PreferredSlotRepository {
 }
 
 func (r *preferredSlotRepository) Create(ctx context.Context, slot *model.PreferredSlot) error {
+	ctx, end := observe(ctx, "preferred_slot", "Create")
+	defer end()
+
 	query := `
-		INSERT INTO preferred_slots (id, email, start_time, end_time, timezone, day_of_week, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO preferred_slots (id, email, start_time, end_time, timezone, day_of_week, rrule, exdates, level, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		slot.ID, slot.Email,
 		slot.StartTime, slot.EndTime, slot.Timezone, slot.DayOfWeek,
+		slot.RRule, exDates(slot.ExDates), slot.Level,
 		slot.CreatedAt, slot.UpdatedAt,
 	)
 	return err
 }
 
+// preferredSlotBatchSize caps the rows per INSERT in CreateBatch so a large
+// import stays well under Postgres' limit of 65535 bind parameters.
+const (
+	preferredSlotBatchSize   = 500
+	preferredSlotColumnCount = 11
+)
+
+func (r *preferredSlotRepository) CreateBatch(ctx context.Context, slots []model.PreferredSlot) error {
+	ctx, end := observe(ctx, "preferred_slot", "CreateBatch")
+	defer end()
+
+	if len(slots) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(slots); start += preferredSlotBatchSize {
+		batch := slots[start:min(start+preferredSlotBatchSize, len(slots))]
+
+		var query strings.Builder
+		query.WriteString(`INSERT INTO preferred_slots (id, email, start_time, end_time, timezone, day_of_week, rrule, exdates, level, created_at, updated_at) VALUES `)
+		args := make([]interface{}, 0, len(batch)*preferredSlotColumnCount)
+		for i, slot := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for c := 1; c <= preferredSlotColumnCount; c++ {
+				if c > 1 {
+					query.WriteString(", ")
+				}
+				query.WriteString("$" + strconv.Itoa(len(args)+c))
+			}
+			query.WriteString(")")
+			args = append(args,
+				slot.ID, slot.Email,
+				slot.StartTime, slot.EndTime, slot.Timezone, slot.DayOfWeek,
+				slot.RRule, exDates(slot.ExDates), slot.Level,
+				slot.CreatedAt, slot.UpdatedAt,
+			)
+		}
+
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (r *preferredSlotRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PreferredSlot, error) {
+	ctx, end := observe(ctx, "preferred_slot", "GetByID")
+	defer end()
+
 	query := `
-		SELECT id, email, start_time, end_time, timezone, day_of_week, created_at, updated_at
+		SELECT id, email, start_time, end_time, timezone, day_of_week, rrule, exdates, level, created_at, updated_at
 		FROM preferred_slots WHERE id = $1
 	`
 	slot := &model.PreferredSlot{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&slot.ID, &slot.Email,
 		&slot.StartTime, &slot.EndTime, &slot.Timezone, &slot.DayOfWeek,
+		&slot.RRule, pq.Array(&slot.ExDates), &slot.Level,
 		&slot.CreatedAt, &slot.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return slot, nil
 }
 
 func (r *preferredSlotRepository) GetByEmail(ctx context.Context, email string) ([]model.PreferredSlot, error) {
+	ctx, end := observe(ctx, "preferred_slot", "GetByEmail")
+	defer end()
+
 	query := `
-		SELECT id, email, start_time, end_time, timezone, day_of_week, created_at, updated_at
+		SELECT id, email, start_time, end_time, timezone, day_of_week, rrule, exdates, level, created_at, updated_at
 		FROM preferred_slots WHERE LOWER(email) = LOWER($1) ORDER BY start_time
 	`
 	rows, err := r.db.QueryContext(ctx, query, email)
@@ -75,6 +145,7 @@ func (r *preferredSlotRepository) GetByEmail(ctx context.Context, email string)
 		err := rows.Scan(
 			&slot.ID, &slot.Email,
 			&slot.StartTime, &slot.EndTime, &slot.Timezone, &slot.DayOfWeek,
+			&slot.RRule, pq.Array(&slot.ExDates), &slot.Level,
 			&slot.CreatedAt, &slot.UpdatedAt,
 		)
 		if err != nil {
@@ -86,12 +157,15 @@ func (r *preferredSlotRepository) GetByEmail(ctx context.Context, email string)
 }
 
 func (r *preferredSlotRepository) GetByEmails(ctx context.Context, emails []string) ([]model.PreferredSlot, error) {
+	ctx, end := observe(ctx, "preferred_slot", "GetByEmails")
+	defer end()
+
 	if len(emails) == 0 {
 		return []model.PreferredSlot{}, nil
 	}
 
 	query := `
-		SELECT id, email, start_time, end_time, timezone, day_of_week, created_at, updated_at
+		SELECT id, email, start_time, end_time, timezone, day_of_week, rrule, exdates, level, created_at, updated_at
 		FROM preferred_slots WHERE LOWER(email) = ANY($1) ORDER BY email, start_time
 	`
 	lowerEmails := make([]string, len(emails))
@@ -111,6 +185,7 @@ func (r *preferredSlotRepository) GetByEmails(ctx context.Context, emails []stri
 		err := rows.Scan(
 			&slot.ID, &slot.Email,
 			&slot.StartTime, &slot.EndTime, &slot.Timezone, &slot.DayOfWeek,
+			&slot.RRule, pq.Array(&slot.ExDates), &slot.Level,
 			&slot.CreatedAt, &slot.UpdatedAt,
 		)
 		if err != nil {
@@ -122,20 +197,35 @@ func (r *preferredSlotRepository) GetByEmails(ctx context.Context, emails []stri
 }
 
 func (r *preferredSlotRepository) Update(ctx context.Context, slot *model.PreferredSlot) error {
+	ctx, end := observe(ctx, "preferred_slot", "Update")
+	defer end()
+
 	query := `
 		UPDATE preferred_slots 
-		SET start_time = $1, end_time = $2, timezone = $3, day_of_week = $4, updated_at = $5 
-		WHERE id = $6
+		SET start_time = $1, end_time = $2, timezone = $3, day_of_week = $4, rrule = $5, exdates = $6, level = $7, updated_at = $8
+		WHERE id = $9
 	`
 	slot.UpdatedAt = time.Now().UTC()
 	_, err := r.db.ExecContext(ctx, query,
-		slot.StartTime, slot.EndTime, slot.Timezone, slot.DayOfWeek, slot.UpdatedAt, slot.ID,
+		slot.StartTime, slot.EndTime, slot.Timezone, slot.DayOfWeek,
+		slot.RRule, exDates(slot.ExDates), slot.Level, slot.UpdatedAt, slot.ID,
 	)
 	return err
 }
 
 func (r *preferredSlotRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "preferred_slot", "Delete")
+	defer end()
+
 	query := `DELETE FROM preferred_slots WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+// exDates stores a missing list as an empty array; the column is NOT NULL.
+func exDates(dates []string) interface{} {
+	if dates == nil {
+		dates = []string{}
+	}
+	return pq.Array(dates)
+}
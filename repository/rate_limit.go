@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// RateLimitStore holds token buckets by key.
+type RateLimitStore interface {
+	// Take takes a token from every bucket if each has one and from none
+	// otherwise, so a request turned away by one limit isn't charged by the
+	// others. Buckets that don't exist yet start full. The decisions are in
+	// the order of buckets.
+	Take(ctx context.Context, buckets []model.RateLimitBucket, now time.Time) ([]model.RateLimitDecision, error)
+	// DeleteIdle drops buckets untouched since before; they would be full
+	// again by now.
+	DeleteIdle(ctx context.Context, before time.Time) (int64, error)
+}
+
+type rateLimitStore struct {
+	db *sql.DB
+}
+
+// NewRateLimitStore keeps buckets in Postgres so every instance shares them.
+func NewRateLimitStore(db *sql.DB) RateLimitStore {
+	return &rateLimitStore{db: db}
+}
+
+func (s *rateLimitStore) Take(ctx context.Context, buckets []model.RateLimitBucket, now time.Time) ([]model.RateLimitDecision, error) {
+	ctx, end := observe(ctx, "rate_limit", "Take")
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// lock in key order so requests sharing buckets can't deadlock
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return buckets[order[a]].Key < buckets[order[b]].Key })
+
+	tokens := make([]float64, len(buckets))
+	decisions := make([]model.RateLimitDecision, len(buckets))
+	allowed := true
+	for _, i := range order {
+		b := buckets[i]
+		// make sure the row exists so concurrent requests queue on its lock
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO NOTHING
+		`, b.Key, float64(b.Limit.Size()), now)
+		if err != nil {
+			return nil, err
+		}
+
+		var updated time.Time
+		err = tx.QueryRowContext(ctx,
+			`SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, b.Key,
+		).Scan(&tokens[i], &updated)
+		if err != nil {
+			return nil, err
+		}
+		// a bucket touched later by a clock ahead of ours isn't refilled
+		// or moved back
+		if now.After(updated) {
+			tokens[i] = b.Limit.Refill(tokens[i], updated, now)
+		}
+		decisions[i] = b.Limit.Check(tokens[i])
+		allowed = allowed && decisions[i].Allowed
+	}
+
+	for _, i := range order {
+		if allowed {
+			tokens[i]--
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE rate_limit_buckets SET tokens = $2, updated_at = GREATEST(updated_at, $3) WHERE key = $1`,
+			buckets[i].Key, tokens[i], now,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decisions, tx.Commit()
+}
+
+func (s *rateLimitStore) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	ctx, end := observe(ctx, "rate_limit", "DeleteIdle")
+	defer end()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
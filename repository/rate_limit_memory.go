@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ram-ks/meeting-service/model"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// memoryRateLimitStore keeps buckets in process, so each instance limits on
+// its own.
+type memoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, buckets []model.RateLimitBucket, now time.Time) ([]model.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	held := make([]*bucket, len(buckets))
+	decisions := make([]model.RateLimitDecision, len(buckets))
+	allowed := true
+	for i, b := range buckets {
+		held[i] = s.buckets[b.Key]
+		if held[i] == nil {
+			held[i] = &bucket{tokens: float64(b.Limit.Size()), updated: now}
+			s.buckets[b.Key] = held[i]
+		}
+		if now.After(held[i].updated) {
+			held[i].tokens = b.Limit.Refill(held[i].tokens, held[i].updated, now)
+			held[i].updated = now
+		}
+		decisions[i] = b.Limit.Check(held[i].tokens)
+		allowed = allowed && decisions[i].Allowed
+	}
+
+	if allowed {
+		for _, b := range held {
+			b.tokens--
+		}
+	}
+	return decisions, nil
+}
+
+func (s *memoryRateLimitStore) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, b := range s.buckets {
+		if b.updated.Before(before) {
+			delete(s.buckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// WorkingHoursRepository stores one profile per email. Emails are stored
+// lowercased, so lookups ignore case.
+type WorkingHoursRepository interface {
+	// Upsert creates or replaces the profile for profile.Email, filling in
+	// its ID and CreatedAt from the stored row.
+	Upsert(ctx context.Context, profile *model.WorkingHoursProfile) error
+	GetByEmail(ctx context.Context, email string) (*model.WorkingHoursProfile, error)
+	GetByEmails(ctx context.Context, emails []string) ([]model.WorkingHoursProfile, error)
+	Delete(ctx context.Context, email string) error
+}
+
+type workingHoursRepository struct {
+	db *sql.DB
+}
+
+func NewWorkingHoursRepository(db *sql.DB) WorkingHoursRepository {
+	return &workingHoursRepository{db: db}
+}
+
+const workingHoursColumns = `id, email, timezone, hours, to_char(not_before, 'HH24:MI'), to_char(not_after, 'HH24:MI'), created_at, updated_at`
+
+func (r *workingHoursRepository) Upsert(ctx context.Context, profile *model.WorkingHoursProfile) error {
+	ctx, end := observe(ctx, "working_hours", "Upsert")
+	defer end()
+
+	hours, err := json.Marshal(profile.Hours)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO working_hours_profiles (email, id, timezone, hours, not_before, not_after, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (email) DO UPDATE
+		SET timezone = EXCLUDED.timezone, hours = EXCLUDED.hours,
+			not_before = EXCLUDED.not_before, not_after = EXCLUDED.not_after,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		strings.ToLower(profile.Email), profile.ID, profile.Timezone, hours,
+		profile.NotBefore, profile.NotAfter,
+		profile.CreatedAt, profile.UpdatedAt,
+	).Scan(&profile.ID, &profile.CreatedAt)
+}
+
+func (r *workingHoursRepository) GetByEmail(ctx context.Context, email string) (*model.WorkingHoursProfile, error) {
+	ctx, end := observe(ctx, "working_hours", "GetByEmail")
+	defer end()
+
+	query := `SELECT ` + workingHoursColumns + ` FROM working_hours_profiles WHERE email = LOWER($1)`
+	profile, err := scanWorkingHours(r.db.QueryRowContext(ctx, query, email))
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+	return profile, nil
+}
+
+func (r *workingHoursRepository) GetByEmails(ctx context.Context, emails []string) ([]model.WorkingHoursProfile, error) {
+	ctx, end := observe(ctx, "working_hours", "GetByEmails")
+	defer end()
+
+	if len(emails) == 0 {
+		return []model.WorkingHoursProfile{}, nil
+	}
+
+	query := `SELECT ` + workingHoursColumns + ` FROM working_hours_profiles WHERE email = ANY($1)`
+	lowerEmails := make([]string, len(emails))
+	for i, e := range emails {
+		lowerEmails[i] = strings.ToLower(e)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowerEmails))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []model.WorkingHoursProfile
+	for rows.Next() {
+		profile, err := scanWorkingHours(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+	return profiles, rows.Err()
+}
+
+func (r *workingHoursRepository) Delete(ctx context.Context, email string) error {
+	ctx, end := observe(ctx, "working_hours", "Delete")
+	defer end()
+
+	query := `DELETE FROM working_hours_profiles WHERE email = LOWER($1)`
+	_, err := r.db.ExecContext(ctx, query, email)
+	return err
+}
+
+func scanWorkingHours(row interface{ Scan(...interface{}) error }) (*model.WorkingHoursProfile, error) {
+	var profile model.WorkingHoursProfile
+	var hours []byte
+	err := row.Scan(
+		&profile.ID, &profile.Email, &profile.Timezone, &hours,
+		&profile.NotBefore, &profile.NotAfter,
+		&profile.CreatedAt, &profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(hours, &profile.Hours); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,131 @@
+// Package router holds the route table. The API is served under /v1; the
+// same routes stay mounted at the root for clients from before versioning,
+// marked deprecated, until their sunset date.
+package router
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/controllers"
+	"github.com/ram-ks/meeting-service/middleware"
+)
+
+// APIPrefix is where the current API version is mounted.
+const APIPrefix = "/v1"
+
+type Handlers struct {
+	Events          *controllers.EventController
+	Availability    *controllers.AvailabilityController
+	FreeRanges      *controllers.FreeRangeController
+	Recommendations *controllers.RecommendationController
+	PreferredSlots  *controllers.PreferredSlotController
+	Blackouts       *controllers.BlackoutController
+	WorkingHours    *controllers.WorkingHoursController
+	Stream          *controllers.StreamController
+
+	// Operational endpoints are not versioned.
+	Health  gin.HandlerFunc
+	Livez   gin.HandlerFunc
+	Readyz  gin.HandlerFunc
+	Metrics gin.HandlerFunc
+}
+
+// Legacy controls the unversioned aliases. With Enabled false only /v1 is
+// served.
+type Legacy struct {
+	Enabled      bool
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+// Register mounts every route on r. The api middleware wraps only the API
+// routes and runs after the legacy aliases' deprecation headers are set, so
+// responses it writes itself, such as idempotent replays, carry them too.
+func Register(r *gin.Engine, h Handlers, legacy Legacy, api ...gin.HandlerFunc) {
+	r.GET("/health", h.Health)
+	r.GET("/livez", h.Livez)
+	r.GET("/readyz", h.Readyz)
+	r.GET("/metrics", h.Metrics)
+
+	registerAPI(r.Group(APIPrefix, api...), h)
+
+	if legacy.Enabled {
+		deprecated := middleware.Deprecated(legacy.DeprecatedAt, legacy.Sunset, APIPrefix)
+		registerAPI(r.Group("", append([]gin.HandlerFunc{deprecated}, api...)...), h)
+	}
+}
+
+// Paths returns the route templates a versioned API route is served at,
+// for settings written against unversioned routes such as rate limits.
+func Paths(route string, legacy bool) []string {
+	if legacy {
+		return []string{APIPrefix + route, route}
+	}
+	return []string{APIPrefix + route}
+}
+
+func registerAPI(api *gin.RouterGroup, h Handlers) {
+	events := api.Group("/events")
+	{
+		events.POST("", h.Events.CreateEvent)
+		events.GET("", h.Events.ListEvents)
+		events.GET("/:id", h.Events.GetEvent)
+		events.PUT("/:id", h.Events.UpdateEvent)
+		events.DELETE("/:id", h.Events.DeleteEvent)
+		events.POST("/:id/restore", h.Events.RestoreEvent)
+		events.POST("/:id/finalize", h.Events.FinalizeEvent)
+		events.GET("/:id/stream", h.Stream.StreamEvent)
+		events.GET("/:id/recommendations", h.Recommendations.GetRecommendations)
+		events.GET("/:id/history", h.Events.GetHistory)
+
+		availability := events.Group("/:id/availability")
+		{
+			availability.POST("", h.Availability.SubmitAvailability)
+			availability.GET("", h.Availability.GetAvailability)
+			availability.GET("/heatmap", h.Availability.GetHeatmap)
+			availability.GET("/:participant_id", h.Availability.GetParticipantAvailability)
+			availability.GET("/:participant_id/history", h.Availability.GetParticipantHistory)
+			availability.PUT("/:availability_id", h.Availability.UpdateAvailability)
+			availability.DELETE("/:availability_id", h.Availability.DeleteAvailability)
+		}
+
+		freeRanges := events.Group("/:id/free-ranges")
+		{
+			freeRanges.POST("", h.FreeRanges.SubmitFreeRanges)
+			freeRanges.GET("", h.FreeRanges.GetFreeRanges)
+		}
+
+		freeWindows := events.Group("/:id/free-windows")
+		{
+			freeWindows.GET("", h.FreeRanges.GetFreeWindows)
+			freeWindows.POST("/promote", h.FreeRanges.PromoteWindow)
+		}
+	}
+
+	preferredSlots := api.Group("/preferred-slots")
+	{
+		preferredSlots.POST("", h.PreferredSlots.CreatePreferredSlot)
+		preferredSlots.POST("/import", h.PreferredSlots.ImportPreferredSlots)
+		preferredSlots.GET("/export", h.PreferredSlots.ExportPreferredSlots)
+		preferredSlots.GET("/email/:email", h.PreferredSlots.GetPreferredSlotsByEmail)
+		preferredSlots.POST("/email/:email/bootstrap", h.PreferredSlots.BootstrapPreferredSlots)
+		preferredSlots.PUT("/:id", h.PreferredSlots.UpdatePreferredSlot)
+		preferredSlots.DELETE("/:id", h.PreferredSlots.DeletePreferredSlot)
+	}
+
+	blackouts := api.Group("/blackouts")
+	{
+		blackouts.POST("", h.Blackouts.CreateBlackout)
+		blackouts.GET("/email/:email", h.Blackouts.GetBlackoutsByEmail)
+		blackouts.PUT("/:id", h.Blackouts.UpdateBlackout)
+		blackouts.DELETE("/:id", h.Blackouts.DeleteBlackout)
+	}
+
+	workingHours := api.Group("/working-hours")
+	{
+		workingHours.PUT("/email/:email", h.WorkingHours.PutWorkingHours)
+		workingHours.GET("/email/:email", h.WorkingHours.GetWorkingHours)
+		workingHours.DELETE("/email/:email", h.WorkingHours.DeleteWorkingHours)
+	}
+}
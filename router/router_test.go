@@ -0,0 +1,95 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ram-ks/meeting-service/controllers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterSuite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	handlers := Handlers{
+		Events:          &controllers.EventController{},
+		Availability:    &controllers.AvailabilityController{},
+		FreeRanges:      &controllers.FreeRangeController{},
+		Recommendations: &controllers.RecommendationController{},
+		PreferredSlots:  &controllers.PreferredSlotController{},
+		Blackouts:       &controllers.BlackoutController{},
+		WorkingHours:    &controllers.WorkingHoursController{},
+		Stream:          &controllers.StreamController{},
+		Health:          ok,
+		Livez:           ok,
+		Readyz:          ok,
+		Metrics:         ok,
+	}
+	legacy := Legacy{
+		Enabled:      true,
+		DeprecatedAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC),
+	}
+
+	routes := func(legacy Legacy) map[string]bool {
+		engine := gin.New()
+		Register(engine, handlers, legacy)
+		set := make(map[string]bool)
+		for _, r := range engine.Routes() {
+			set[r.Method+" "+r.Path] = true
+		}
+		return set
+	}
+
+	t.Run("EveryVersionedRouteHasLegacyAlias", func(t *testing.T) {
+		set := routes(legacy)
+
+		versioned := 0
+		for route := range set {
+			method, path, _ := strings.Cut(route, " ")
+			if !strings.HasPrefix(path, APIPrefix+"/") {
+				continue
+			}
+			versioned++
+			assert.True(t, set[method+" "+strings.TrimPrefix(path, APIPrefix)], "no legacy alias for %s", route)
+		}
+		assert.Equal(t, versioned*2+4, len(set))
+		assert.True(t, set["POST /v1/events/:id/availability"])
+		assert.True(t, set["GET /livez"])
+		assert.False(t, set["GET /v1/livez"])
+	})
+
+	t.Run("LegacyAliasesCanBeTurnedOff", func(t *testing.T) {
+		set := routes(Legacy{})
+
+		assert.True(t, set["GET /v1/events/:id"])
+		assert.False(t, set["GET /events/:id"])
+	})
+
+	t.Run("APIMiddlewareRunsAfterDeprecationHeaders", func(t *testing.T) {
+		engine := gin.New()
+		// stands in for a middleware that answers on its own, like an
+		// idempotent replay
+		answer := func(c *gin.Context) { c.AbortWithStatus(http.StatusCreated) }
+		Register(engine, handlers, legacy, answer)
+
+		for path, deprecated := range map[string]bool{"/events": true, "/v1/events": false} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", path, nil)
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code, path)
+			assert.Equal(t, deprecated, w.Header().Get("Deprecation") != "", path)
+		}
+	})
+
+	t.Run("Paths", func(t *testing.T) {
+		assert.Equal(t, []string{"/v1/events", "/events"}, Paths("/events", true))
+		assert.Equal(t, []string{"/v1/events"}, Paths("/events", false))
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+)
+
+const systemActor = "system"
+
+type actorKey struct{}
+
+// WithActor attaches the identity responsible for the mutations made with ctx.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return systemActor
+}
+
+// auditRecorder writes audit entries after a mutation has succeeded. A failed
+// write is logged rather than returned so the caller doesn't retry a change
+// that was already applied.
+type auditRecorder struct {
+	repo repository.AuditRepository
+}
+
+func (a auditRecorder) record(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID uuid.UUID, eventID *uuid.UUID, before, after interface{}) {
+	if a.repo == nil {
+		return
+	}
+
+	entry := &model.AuditEntry{
+		ID:         uuid.New(),
+		Actor:      ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		EventID:    eventID,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := a.repo.Create(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit entry",
+			"action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+func snapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
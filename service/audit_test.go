@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) ListByEventID(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error) {
+	args := m.Called(ctx, eventID, limit, cursor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuditPage), args.Error(1)
+}
+
+func TestAuditSuite(t *testing.T) {
+	t.Run("SubmitAvailability_RecordsPreviousAnswerAndStatusChange", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockAuditRepo := new(MockAuditRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, mockAuditRepo, nil)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participantID := uuid.New()
+		previousID := uuid.New()
+
+		event := &model.Event{
+			ID:            eventID,
+			ProposedSlots: []model.TimeSlot{{ID: slotID, EventID: eventID}},
+			Participants: []model.Participant{
+				{ID: participantID, EventID: eventID, Status: model.ParticipantStatusPending},
+			},
+		}
+		previous := model.Availability{
+			ID:            previousID,
+			EventID:       eventID,
+			ParticipantID: participantID,
+			SlotID:        slotID,
+			Status:        model.AvailabilityStatusAvailable,
+			CreatedAt:     time.Now().Add(-time.Hour),
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("SaveAnswers", mock.Anything, participantID, "", mock.Anything).Run(func(args mock.Arguments) {
+			// the repository keeps the replaced row's id
+			args.Get(3).([]*model.Availability)[0].ID = previousID
+		}).Return([]model.Availability{previous}, nil)
+		mockEventRepo.On("UpdateParticipantStatus", mock.Anything, participantID, model.ParticipantStatusResponded).Return(nil)
+
+		var entries []*model.AuditEntry
+		mockAuditRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			entries = append(entries, args.Get(1).(*model.AuditEntry))
+		}).Return(nil)
+
+		ctx := WithActor(context.Background(), "participant:"+participantID.String())
+		err := svc.SubmitAvailability(ctx, eventID, model.SubmitAvailabilityRequest{
+			ParticipantID: participantID,
+			Slots: []model.SlotAvailabilityRequest{
+				{SlotID: slotID, Status: model.AvailabilityStatusUnavailable},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		availEntry := entries[0]
+		assert.Equal(t, model.AuditActionUpdate, availEntry.Action)
+		assert.Equal(t, model.AuditEntityAvailability, availEntry.EntityType)
+		assert.Equal(t, previousID, availEntry.EntityID)
+		assert.Equal(t, "participant:"+participantID.String(), availEntry.Actor)
+
+		var before, after model.Availability
+		assert.NoError(t, json.Unmarshal(availEntry.Before, &before))
+		assert.NoError(t, json.Unmarshal(availEntry.After, &after))
+		assert.Equal(t, model.AvailabilityStatusAvailable, before.Status)
+		assert.Equal(t, model.AvailabilityStatusUnavailable, after.Status)
+
+		participantEntry := entries[1]
+		assert.Equal(t, model.AuditEntityParticipant, participantEntry.EntityType)
+		assert.Equal(t, participantID, participantEntry.EntityID)
+	})
+
+	t.Run("SubmitAvailability_AuditWriteFails_StillCompletes", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		hub := pubsub.NewHub()
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, mockAuditRepo, hub)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participantID := uuid.New()
+
+		event := &model.Event{
+			ID:            eventID,
+			ProposedSlots: []model.TimeSlot{{ID: slotID, EventID: eventID}},
+			Participants: []model.Participant{
+				{ID: participantID, EventID: eventID, Status: model.ParticipantStatusPending},
+			},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("SaveAnswers", mock.Anything, participantID, "", mock.Anything).Return([]model.Availability{}, nil)
+		mockEventRepo.On("UpdateParticipantStatus", mock.Anything, participantID, model.ParticipantStatusResponded).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+
+		err := svc.SubmitAvailability(context.Background(), eventID, model.SubmitAvailabilityRequest{
+			ParticipantID: participantID,
+			Slots: []model.SlotAvailabilityRequest{
+				{SlotID: slotID, Status: model.AvailabilityStatusAvailable},
+			},
+		})
+
+		assert.NoError(t, err)
+		mockEventRepo.AssertCalled(t, "UpdateParticipantStatus", mock.Anything, participantID, model.ParticipantStatusResponded)
+		assert.Equal(t, model.StreamAvailabilitySubmitted, (<-sub.C).Type)
+		assert.Equal(t, model.StreamParticipantStatusChanged, (<-sub.C).Type)
+	})
+
+	t.Run("ActorFromContext_DefaultsToSystem", func(t *testing.T) {
+		assert.Equal(t, "system", ActorFromContext(context.Background()))
+	})
+}
@@ -2,22 +2,18 @@ package service
 
 import (
 	"context"
-	"errors"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/metrics"
 	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
 	"github.com/ram-ks/meeting-service/repository"
-)
-
-var (
-	ErrEventNotFound        = errors.New("event not found")
-	ErrSlotNotFound         = errors.New("slot not found")
-	ErrInvalidStatus        = errors.New("invalid event status for this operation")
-	ErrSlotNotInEvent       = errors.New("slot does not belong to this event")
-	ErrInvalidTimeFormat    = errors.New("invalid time format")
-	ErrParticipantNotFound  = errors.New("participant not found")
-	ErrAvailabilityNotFound = errors.New("availability not found")
+	"github.com/ram-ks/meeting-service/validation"
 )
 
 type AvailabilityService interface {
@@ -26,78 +22,102 @@ type AvailabilityService interface {
 	GetParticipantAvailability(ctx context.Context, eventID, participantID uuid.UUID) ([]model.Availability, error)
 	UpdateAvailability(ctx context.Context, availabilityID uuid.UUID, req model.UpdateAvailabilityRequest) (*model.Availability, error)
 	DeleteAvailability(ctx context.Context, availabilityID uuid.UUID) error
+	GetParticipantHistory(ctx context.Context, eventID, participantID uuid.UUID) (*model.ParticipantAvailabilityHistory, error)
+	GetHeatmap(ctx context.Context, eventID uuid.UUID, bucket time.Duration) (*model.Heatmap, error)
 }
 
 type availabilityService struct {
 	availRepo repository.AvailabilityRepository
 	eventRepo repository.EventRepository
+	audit     auditRecorder
+	stream    streamPublisher
 }
 
-func NewAvailabilityService(availRepo repository.AvailabilityRepository, eventRepo repository.EventRepository) AvailabilityService {
+func NewAvailabilityService(availRepo repository.AvailabilityRepository, eventRepo repository.EventRepository, auditRepo repository.AuditRepository, publisher pubsub.Publisher) AvailabilityService {
 	return &availabilityService{
 		availRepo: availRepo,
 		eventRepo: eventRepo,
+		audit:     auditRecorder{repo: auditRepo},
+		stream:    streamPublisher{pub: publisher},
 	}
 }
 
 func (s *availabilityService) SubmitAvailability(ctx context.Context, eventID uuid.UUID, req model.SubmitAvailabilityRequest) error {
 	event, err := s.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
-		return ErrEventNotFound
+		return notFound(err, ErrEventNotFound)
 	}
 
-	participantFound := false
-	for _, p := range event.Participants {
+	var participant *model.Participant
+	for i, p := range event.Participants {
 		if p.ID == req.ParticipantID {
-			participantFound = true
+			participant = &event.Participants[i]
 			break
 		}
 	}
-	if !participantFound {
+	if participant == nil {
 		return ErrParticipantNotFound
 	}
 
 	now := time.Now().UTC()
+	if err := checkAcceptingResponses(event, now); err != nil {
+		return err
+	}
 
-	for _, slotAvail := range req.Slots {
-		slotFound := false
-		for _, slot := range event.ProposedSlots {
-			if slot.ID == slotAvail.SlotID {
-				slotFound = true
-				break
-			}
-		}
-		if !slotFound {
-			return ErrSlotNotInEvent
+	eventSlots := make(map[uuid.UUID]bool, len(event.ProposedSlots))
+	for _, slot := range event.ProposedSlots {
+		eventSlots[slot.ID] = true
+	}
+
+	// Check every answer before writing any so a bad entry doesn't leave a
+	// partial submission behind
+	var v validation.Errors
+	answered := make(map[uuid.UUID]int, len(req.Slots))
+	availabilities := make([]*model.Availability, 0, len(req.Slots))
+	for i, slotAvail := range req.Slots {
+		prefix := validation.Index("slots", i) + "."
+		if !eventSlots[slotAvail.SlotID] {
+			v.Add(prefix+"slot_id", "%s", ErrSlotNotInEvent.Message)
+		} else if first, dup := answered[slotAvail.SlotID]; dup {
+			v.Add(prefix+"slot_id", "duplicates %s.slot_id", validation.Index("slots", first))
+		} else {
+			answered[slotAvail.SlotID] = i
 		}
 
-		availability := &model.Availability{
+		from, to := parseAnswerWindow(&v, prefix, slotAvail.Status, slotAvail.AvailableFrom, slotAvail.AvailableTo)
+		availabilities = append(availabilities, &model.Availability{
 			ID:            uuid.New(),
 			EventID:       eventID,
 			ParticipantID: req.ParticipantID,
 			SlotID:        slotAvail.SlotID,
 			Status:        slotAvail.Status,
+			AvailableFrom: from,
+			AvailableTo:   to,
+			Note:          cleanNote(slotAvail.Note),
 			CreatedAt:     now,
 			UpdatedAt:     now,
-		}
+		})
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
 
-		if slotAvail.AvailableFrom != nil {
-			t, err := time.Parse(time.RFC3339, *slotAvail.AvailableFrom)
-			if err != nil {
-				return ErrInvalidTimeFormat
-			}
-			availability.AvailableFrom = &t
-		}
-		if slotAvail.AvailableTo != nil {
-			t, err := time.Parse(time.RFC3339, *slotAvail.AvailableTo)
-			if err != nil {
-				return ErrInvalidTimeFormat
-			}
-			availability.AvailableTo = &t
-		}
+	// answers are overwritten in place, so keep the ones replaced for the
+	// audit log
+	replaced, err := s.availRepo.SaveAnswers(ctx, req.ParticipantID, cleanNote(req.Note), availabilities)
+	if err != nil {
+		return err
+	}
+	previous := make(map[uuid.UUID]model.Availability, len(replaced))
+	for _, a := range replaced {
+		previous[a.SlotID] = a
+	}
 
-		if err := s.availRepo.Upsert(ctx, availability); err != nil {
-			return err
+	for _, availability := range availabilities {
+		if prev, existed := previous[availability.SlotID]; existed {
+			s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityAvailability, availability.ID, &eventID, prev, availability)
+		} else {
+			s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityAvailability, availability.ID, &eventID, nil, availability)
 		}
 	}
 
@@ -105,13 +125,35 @@ func (s *availabilityService) SubmitAvailability(ctx context.Context, eventID uu
 		return err
 	}
 
+	metrics.AvailabilitySubmissions.Inc()
+
+	slotIDs := make([]uuid.UUID, 0, len(availabilities))
+	for _, availability := range availabilities {
+		slotIDs = append(slotIDs, availability.SlotID)
+	}
+	s.stream.publish(ctx, model.StreamAvailabilitySubmitted, eventID, model.AvailabilitySubmittedData{
+		ParticipantID: req.ParticipantID,
+		SlotIDs:       slotIDs,
+	})
+
+	if participant.Status != model.ParticipantStatusResponded {
+		before := *participant
+		participant.Status = model.ParticipantStatusResponded
+		s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityParticipant, participant.ID, &eventID, before, participant)
+		s.stream.publish(ctx, model.StreamParticipantStatusChanged, eventID, model.ParticipantStatusChangedData{
+			ParticipantID: participant.ID,
+			From:          before.Status,
+			To:            participant.Status,
+		})
+	}
+
 	return nil
 }
 
 func (s *availabilityService) GetAvailability(ctx context.Context, eventID uuid.UUID) ([]model.Availability, error) {
 	_, err := s.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
-		return nil, ErrEventNotFound
+		return nil, notFound(err, ErrEventNotFound)
 	}
 
 	return s.availRepo.GetByEventID(ctx, eventID)
@@ -135,37 +177,175 @@ func (s *availabilityService) GetParticipantAvailability(ctx context.Context, ev
 func (s *availabilityService) UpdateAvailability(ctx context.Context, availabilityID uuid.UUID, req model.UpdateAvailabilityRequest) (*model.Availability, error) {
 	availability, err := s.availRepo.GetByID(ctx, availabilityID)
 	if err != nil {
-		return nil, ErrAvailabilityNotFound
+		return nil, notFound(err, ErrAvailabilityNotFound)
 	}
 
-	availability.Status = req.Status
+	event, err := s.eventRepo.GetByID(ctx, availability.EventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	if err := checkAcceptingResponses(event, time.Now().UTC()); err != nil {
+		return nil, err
+	}
 
-	if req.AvailableFrom != nil {
-		t, err := time.Parse(time.RFC3339, *req.AvailableFrom)
-		if err != nil {
-			return nil, ErrInvalidTimeFormat
-		}
-		availability.AvailableFrom = &t
+	var v validation.Errors
+	from, to := parseAnswerWindow(&v, "", req.Status, req.AvailableFrom, req.AvailableTo)
+	if from == nil {
+		from = availability.AvailableFrom
 	}
-	if req.AvailableTo != nil {
-		t, err := time.Parse(time.RFC3339, *req.AvailableTo)
-		if err != nil {
-			return nil, ErrInvalidTimeFormat
-		}
-		availability.AvailableTo = &t
+	if to == nil {
+		to = availability.AvailableTo
+	}
+	if v.Empty() && from != nil && to != nil {
+		v.Range("available_to", "available_from", *from, *to)
 	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	before := *availability
+	availability.Status = req.Status
+	availability.AvailableFrom = from
+	availability.AvailableTo = to
+	if req.Note != nil {
+		availability.Note = cleanNote(*req.Note)
+	}
+	availability.UpdatedAt = time.Now().UTC()
 
-	if err := s.availRepo.Update(ctx, availability); err != nil {
+	var revision *model.AvailabilityRevision
+	if !before.SameAnswer(*availability) {
+		revision = availability.NewRevision()
+	}
+	if err := s.availRepo.Update(ctx, availability, revision); err != nil {
 		return nil, err
 	}
 
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityAvailability, availability.ID, &availability.EventID, before, availability)
+	s.stream.publish(ctx, model.StreamAvailabilityUpdated, availability.EventID, model.AvailabilitySubmittedData{
+		ParticipantID: availability.ParticipantID,
+		SlotIDs:       []uuid.UUID{availability.SlotID},
+	})
 	return availability, nil
 }
 
 func (s *availabilityService) DeleteAvailability(ctx context.Context, availabilityID uuid.UUID) error {
-	_, err := s.availRepo.GetByID(ctx, availabilityID)
+	availability, err := s.availRepo.GetByID(ctx, availabilityID)
 	if err != nil {
-		return ErrAvailabilityNotFound
+		return notFound(err, ErrAvailabilityNotFound)
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, availability.EventID)
+	if err != nil {
+		return notFound(err, ErrEventNotFound)
+	}
+	if err := checkAcceptingResponses(event, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	revision := availability.NewRevision()
+	revision.Deleted = true
+	revision.CreatedAt = time.Now().UTC()
+	if err := s.availRepo.Delete(ctx, availability, revision); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, model.AuditActionDelete, model.AuditEntityAvailability, availability.ID, &availability.EventID, availability, nil)
+	s.stream.publish(ctx, model.StreamAvailabilityDeleted, availability.EventID, model.AvailabilitySubmittedData{
+		ParticipantID: availability.ParticipantID,
+		SlotIDs:       []uuid.UUID{availability.SlotID},
+	})
+	return nil
+}
+
+func (s *availabilityService) GetParticipantHistory(ctx context.Context, eventID, participantID uuid.UUID) (*model.ParticipantAvailabilityHistory, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	participantFound := false
+	for _, p := range event.Participants {
+		if p.ID == participantID {
+			participantFound = true
+			break
+		}
+	}
+	if !participantFound {
+		return nil, ErrParticipantNotFound
+	}
+
+	revisions, err := s.availRepo.GetRevisionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.ParticipantAvailabilityHistory{
+		EventID:       eventID,
+		ParticipantID: participantID,
+		Revisions:     []model.AvailabilityRevision{},
+	}
+
+	// revisions arrive ordered by slot then revision number, so the previous
+	// entry for the same slot is the one to diff against
+	latest := make(map[uuid.UUID]model.AvailabilityRevision)
+	for _, rev := range revisions {
+		if rev.ParticipantID != participantID {
+			continue
+		}
+		if prev, ok := latest[rev.SlotID]; ok {
+			rev.Changes = diffRevisions(prev, rev)
+		}
+		latest[rev.SlotID] = rev
+		history.Revisions = append(history.Revisions, rev)
+	}
+
+	sort.SliceStable(history.Revisions, func(i, j int) bool {
+		return history.Revisions[i].CreatedAt.Before(history.Revisions[j].CreatedAt)
+	})
+
+	return history, nil
+}
+
+// cleanNote trims a participant's note and escapes it so it can be shown
+// in HTML as is.
+func cleanNote(note string) string {
+	return html.EscapeString(strings.TrimSpace(note))
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func diffRevisions(prev, cur model.AvailabilityRevision) []model.AvailabilityChange {
+	var changes []model.AvailabilityChange
+	if prev.Deleted != cur.Deleted {
+		from, to := strconv.FormatBool(prev.Deleted), strconv.FormatBool(cur.Deleted)
+		changes = append(changes, model.AvailabilityChange{Field: "deleted", From: &from, To: &to})
+	}
+	if prev.Status != cur.Status {
+		from, to := string(prev.Status), string(cur.Status)
+		changes = append(changes, model.AvailabilityChange{Field: "status", From: &from, To: &to})
+	}
+	if !sameTime(prev.AvailableFrom, cur.AvailableFrom) {
+		changes = append(changes, model.AvailabilityChange{
+			Field: "available_from", From: formatTime(prev.AvailableFrom), To: formatTime(cur.AvailableFrom),
+		})
+	}
+	if !sameTime(prev.AvailableTo, cur.AvailableTo) {
+		changes = append(changes, model.AvailabilityChange{
+			Field: "available_to", From: formatTime(prev.AvailableTo), To: formatTime(cur.AvailableTo),
+		})
+	}
+	return changes
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
 	}
-	return s.availRepo.Delete(ctx, availabilityID)
+	s := t.UTC().Format(time.RFC3339)
+	return &s
 }
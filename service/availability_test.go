@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAvailabilityServiceSuite(t *testing.T) {
+	t.Run("GetParticipantHistory_DiffsConsecutiveRevisions", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participantID := uuid.New()
+		otherParticipant := uuid.New()
+		base := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+		from := time.Date(2026, 2, 12, 10, 30, 0, 0, time.UTC)
+
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participantID, EventID: eventID},
+				{ID: otherParticipant, EventID: eventID},
+			},
+		}
+		revisions := []model.AvailabilityRevision{
+			{ParticipantID: participantID, SlotID: slotID, Revision: 1, Status: model.AvailabilityStatusAvailable, CreatedAt: base},
+			{ParticipantID: participantID, SlotID: slotID, Revision: 2, Status: model.AvailabilityStatusPartial, AvailableFrom: &from, CreatedAt: base.Add(time.Hour)},
+			{ParticipantID: otherParticipant, SlotID: slotID, Revision: 1, Status: model.AvailabilityStatusUnavailable, CreatedAt: base},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return(revisions, nil)
+
+		history, err := svc.GetParticipantHistory(context.Background(), eventID, participantID)
+
+		assert.NoError(t, err)
+		assert.Len(t, history.Revisions, 2)
+		assert.Empty(t, history.Revisions[0].Changes)
+
+		changes := history.Revisions[1].Changes
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "status", changes[0].Field)
+		assert.Equal(t, "available", *changes[0].From)
+		assert.Equal(t, "partial", *changes[0].To)
+		assert.Equal(t, "available_from", changes[1].Field)
+		assert.Nil(t, changes[1].From)
+		assert.Equal(t, "2026-02-12T10:30:00Z", *changes[1].To)
+	})
+
+	t.Run("GetParticipantHistory_ShowsDeletedAnswers", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participantID := uuid.New()
+		base := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+
+		revisions := []model.AvailabilityRevision{
+			{ParticipantID: participantID, SlotID: slotID, Revision: 1, Status: model.AvailabilityStatusAvailable, CreatedAt: base},
+			{ParticipantID: participantID, SlotID: slotID, Revision: 2, Status: model.AvailabilityStatusAvailable, Deleted: true, CreatedAt: base.Add(time.Hour)},
+			{ParticipantID: participantID, SlotID: slotID, Revision: 3, Status: model.AvailabilityStatusUnavailable, CreatedAt: base.Add(2 * time.Hour)},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{
+			ID:           eventID,
+			Participants: []model.Participant{{ID: participantID, EventID: eventID}},
+		}, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return(revisions, nil)
+
+		history, err := svc.GetParticipantHistory(context.Background(), eventID, participantID)
+
+		assert.NoError(t, err)
+		assert.Len(t, history.Revisions, 3)
+		assert.True(t, history.Revisions[1].Deleted)
+		deleted := history.Revisions[1].Changes
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, "deleted", deleted[0].Field)
+		assert.Equal(t, "false", *deleted[0].From)
+		assert.Equal(t, "true", *deleted[0].To)
+
+		changes := history.Revisions[2].Changes
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "deleted", changes[0].Field)
+		assert.Equal(t, "true", *changes[0].From)
+		assert.Equal(t, "status", changes[1].Field)
+	})
+
+	t.Run("GetParticipantHistory_UnknownParticipant", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		eventID := uuid.New()
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID}, nil)
+
+		history, err := svc.GetParticipantHistory(context.Background(), eventID, uuid.New())
+
+		assert.Nil(t, history)
+		assert.ErrorIs(t, err, ErrParticipantNotFound)
+		mockAvailRepo.AssertNotCalled(t, "GetRevisionsByEventID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubmitAvailability_StoresEscapedNotes", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		eventID := uuid.New()
+		participantID := uuid.New()
+		first, second := uuid.New(), uuid.New()
+		event := &model.Event{
+			ID:            eventID,
+			ProposedSlots: []model.TimeSlot{{ID: first}, {ID: second}},
+			Participants:  []model.Participant{{ID: participantID, Status: model.ParticipantStatusResponded}},
+		}
+
+		var stored []*model.Availability
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockEventRepo.On("UpdateParticipantStatus", mock.Anything, participantID, model.ParticipantStatusResponded).Return(nil)
+		mockAvailRepo.On("SaveAnswers", mock.Anything, participantID, "travelling &lt;that week&gt;", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(3).([]*model.Availability)
+		}).Return([]model.Availability{}, nil)
+
+		err := svc.SubmitAvailability(context.Background(), eventID, model.SubmitAvailabilityRequest{
+			ParticipantID: participantID,
+			Note:          " travelling <that week> ",
+			Slots: []model.SlotAvailabilityRequest{
+				{SlotID: first, Status: model.AvailabilityStatusPartial, Note: "<script>alert(1)</script> only the first 20 minutes & then I'm off"},
+				{SlotID: second, Status: model.AvailabilityStatusAvailable},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, stored, 2)
+		assert.Equal(t, "&lt;script&gt;alert(1)&lt;/script&gt; only the first 20 minutes &amp; then I&#39;m off", stored[0].Note)
+		assert.Empty(t, stored[1].Note)
+	})
+
+	t.Run("SubmitAvailability_PastDeadline", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		eventID := uuid.New()
+		participantID := uuid.New()
+		slotID := uuid.New()
+		respondBy := time.Now().Add(-time.Minute)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{
+			ID:            eventID,
+			Status:        model.EventStatusOpen,
+			RespondBy:     &respondBy,
+			ProposedSlots: []model.TimeSlot{{ID: slotID}},
+			Participants:  []model.Participant{{ID: participantID}},
+		}, nil)
+
+		err := svc.SubmitAvailability(context.Background(), eventID, model.SubmitAvailabilityRequest{
+			ParticipantID: participantID,
+			Slots:         []model.SlotAvailabilityRequest{{SlotID: slotID, Status: model.AvailabilityStatusAvailable}},
+		})
+
+		assert.ErrorIs(t, err, ErrResponsesClosed)
+		mockAvailRepo.AssertNotCalled(t, "SaveAnswers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("UpdateAvailability_UnchangedAnswerSkipsRevision", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		availabilityID := uuid.New()
+		eventID := uuid.New()
+		existing := &model.Availability{ID: availabilityID, EventID: eventID, Status: model.AvailabilityStatusAvailable}
+
+		mockAvailRepo.On("GetByID", mock.Anything, availabilityID).Return(existing, nil)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, Status: model.EventStatusOpen}, nil)
+		mockAvailRepo.On("Update", mock.Anything, existing, (*model.AvailabilityRevision)(nil)).Return(nil)
+
+		_, err := svc.UpdateAvailability(context.Background(), availabilityID, model.UpdateAvailabilityRequest{
+			Status: model.AvailabilityStatusAvailable,
+		})
+
+		assert.NoError(t, err)
+		mockAvailRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateAndDeleteAvailability_Publish", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		hub := pubsub.NewHub()
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, hub)
+
+		availabilityID := uuid.New()
+		eventID := uuid.New()
+		participantID := uuid.New()
+		slotID := uuid.New()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+
+		existing := func() *model.Availability {
+			return &model.Availability{ID: availabilityID, EventID: eventID, ParticipantID: participantID, SlotID: slotID, Status: model.AvailabilityStatusAvailable}
+		}
+		mockAvailRepo.On("GetByID", mock.Anything, availabilityID).Return(existing(), nil).Once()
+		mockAvailRepo.On("GetByID", mock.Anything, availabilityID).Return(existing(), nil)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, Status: model.EventStatusOpen}, nil)
+		mockAvailRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockAvailRepo.On("Delete", mock.Anything, mock.Anything, mock.MatchedBy(func(rev *model.AvailabilityRevision) bool {
+			return rev.Deleted && rev.AvailabilityID == availabilityID && rev.Status == model.AvailabilityStatusAvailable
+		})).Return(nil)
+
+		_, err := svc.UpdateAvailability(context.Background(), availabilityID, model.UpdateAvailabilityRequest{
+			Status: model.AvailabilityStatusUnavailable,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, svc.DeleteAvailability(context.Background(), availabilityID))
+
+		for _, want := range []model.StreamEventType{model.StreamAvailabilityUpdated, model.StreamAvailabilityDeleted} {
+			msg := <-sub.C
+			assert.Equal(t, want, msg.Type)
+			assert.JSONEq(t, `{"participant_id":"`+participantID.String()+`","slot_ids":["`+slotID.String()+`"]}`, string(msg.Data))
+		}
+	})
+
+	t.Run("DeleteAvailability_ClosedEvent", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+
+		svc := NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+
+		availabilityID := uuid.New()
+		eventID := uuid.New()
+		mockAvailRepo.On("GetByID", mock.Anything, availabilityID).Return(&model.Availability{ID: availabilityID, EventID: eventID}, nil)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, Status: model.EventStatusClosed}, nil)
+
+		err := svc.DeleteAvailability(context.Background(), availabilityID)
+
+		assert.ErrorIs(t, err, ErrResponsesClosed)
+		mockAvailRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetHeatmap", func(t *testing.T) {
+		eventID := uuid.New()
+		ana, bo, cy := uuid.New(), uuid.New(), uuid.New()
+		day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+		at := func(hour, minute int) time.Time {
+			return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		}
+		first := model.TimeSlot{ID: uuid.New(), StartTime: at(9, 0), EndTime: at(10, 0)}
+		second := model.TimeSlot{ID: uuid.New(), StartTime: at(10, 0), EndTime: at(11, 0)}
+		event := &model.Event{
+			ID:            eventID,
+			ProposedSlots: []model.TimeSlot{second, first},
+			Participants: []model.Participant{
+				{ID: ana, Name: "Ana"},
+				{ID: bo, Name: "Bo"},
+				{ID: cy, Name: "Cy"},
+			},
+		}
+		until := at(10, 30)
+		availabilities := []model.Availability{
+			{ParticipantID: ana, SlotID: first.ID, Status: model.AvailabilityStatusAvailable},
+			{ParticipantID: ana, SlotID: second.ID, Status: model.AvailabilityStatusPartial, AvailableTo: &until},
+			{ParticipantID: bo, SlotID: first.ID, Status: model.AvailabilityStatusIfNeeded},
+			{ParticipantID: bo, SlotID: second.ID, Status: model.AvailabilityStatusUnavailable},
+		}
+		newService := func() AvailabilityService {
+			mockEventRepo := new(MockEventRepository)
+			mockAvailRepo := new(MockAvailabilityRepository)
+			mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+			mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+			return NewAvailabilityService(mockAvailRepo, mockEventRepo, nil, nil)
+		}
+
+		t.Run("BuildsGridInSlotOrder", func(t *testing.T) {
+			heatmap, err := newService().GetHeatmap(context.Background(), eventID, 0)
+
+			assert.NoError(t, err)
+			assert.Len(t, heatmap.Slots, 2)
+			assert.Equal(t, first.ID, heatmap.Slots[0].SlotID)
+			assert.Equal(t, []model.AvailabilityStatus{model.AvailabilityStatusAvailable, model.AvailabilityStatusIfNeeded, ""}, heatmap.Slots[0].Statuses)
+			assert.Equal(t, model.HeatmapTotals{Available: 1, IfNeeded: 1, NoResponse: 1}, heatmap.Slots[0].Totals)
+			assert.Equal(t, model.HeatmapTotals{Partial: 1, Unavailable: 1, NoResponse: 1}, heatmap.Slots[1].Totals)
+			assert.Equal(t, float64(100), heatmap.Participants[0].Coverage)
+			assert.Equal(t, float64(0), heatmap.Participants[2].Coverage)
+			assert.Empty(t, heatmap.Buckets)
+		})
+
+		t.Run("BucketsHonourPartialWindows", func(t *testing.T) {
+			heatmap, err := newService().GetHeatmap(context.Background(), eventID, 30*time.Minute)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "30m0s", heatmap.BucketSize)
+			assert.Len(t, heatmap.Buckets, 4)
+			free := make([]int, len(heatmap.Buckets))
+			ifNeeded := make([]int, len(heatmap.Buckets))
+			for i, b := range heatmap.Buckets {
+				free[i], ifNeeded[i] = b.Free, b.IfNeeded
+				assert.Equal(t, 3, b.Total)
+			}
+			// ana's partial answer ends at 10:30
+			assert.Equal(t, []int{1, 1, 1, 0}, free)
+			assert.Equal(t, []int{1, 1, 0, 0}, ifNeeded)
+			assert.Equal(t, at(10, 30), heatmap.Buckets[3].StartTime)
+		})
+
+		t.Run("TooManyBuckets", func(t *testing.T) {
+			long := *event
+			long.ProposedSlots = []model.TimeSlot{first, {ID: uuid.New(), StartTime: at(9, 0).AddDate(1, 0, 0), EndTime: at(10, 0).AddDate(1, 0, 0)}}
+			mockEventRepo := new(MockEventRepository)
+			mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&long, nil)
+			svc := NewAvailabilityService(new(MockAvailabilityRepository), mockEventRepo, nil, nil)
+
+			_, err := svc.GetHeatmap(context.Background(), eventID, 15*time.Minute)
+
+			assert.ErrorIs(t, err, apperr.ErrBadRequest)
+		})
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+type BlackoutService interface {
+	Create(ctx context.Context, req model.CreateBlackoutRequest) (*model.Blackout, error)
+	GetByEmail(ctx context.Context, email string) ([]model.Blackout, error)
+	Update(ctx context.Context, blackoutID uuid.UUID, req model.UpdateBlackoutRequest) (*model.Blackout, error)
+	Delete(ctx context.Context, blackoutID uuid.UUID) error
+}
+
+type blackoutService struct {
+	repo  repository.BlackoutRepository
+	audit auditRecorder
+}
+
+func NewBlackoutService(repo repository.BlackoutRepository, auditRepo repository.AuditRepository) BlackoutService {
+	return &blackoutService{
+		repo:  repo,
+		audit: auditRecorder{repo: auditRepo},
+	}
+}
+
+func (s *blackoutService) Create(ctx context.Context, req model.CreateBlackoutRequest) (*model.Blackout, error) {
+	now := time.Now().UTC()
+	blackout := &model.Blackout{
+		ID:        uuid.New(),
+		Email:     req.Email,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Timezone:  req.Timezone,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var v validation.Errors
+	if checkBlackout(&v, blackout); !v.Empty() {
+		return nil, v.Err()
+	}
+
+	if err := s.repo.Create(ctx, blackout); err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityBlackout, blackout.ID, nil, nil, blackout)
+	return blackout, nil
+}
+
+func (s *blackoutService) GetByEmail(ctx context.Context, email string) ([]model.Blackout, error) {
+	return s.repo.GetByEmail(ctx, email)
+}
+
+func (s *blackoutService) Update(ctx context.Context, blackoutID uuid.UUID, req model.UpdateBlackoutRequest) (*model.Blackout, error) {
+	blackout, err := s.repo.GetByID(ctx, blackoutID)
+	if err != nil {
+		return nil, notFound(err, ErrBlackoutNotFound)
+	}
+
+	before := *blackout
+	if req.StartDate != nil {
+		blackout.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		blackout.EndDate = *req.EndDate
+	}
+	if req.Timezone != nil {
+		blackout.Timezone = *req.Timezone
+	}
+	if req.Reason != nil {
+		blackout.Reason = strings.TrimSpace(*req.Reason)
+	}
+
+	var v validation.Errors
+	if checkBlackout(&v, blackout); !v.Empty() {
+		return nil, v.Err()
+	}
+
+	if err := s.repo.Update(ctx, blackout); err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityBlackout, blackout.ID, nil, before, blackout)
+	return blackout, nil
+}
+
+func (s *blackoutService) Delete(ctx context.Context, blackoutID uuid.UUID) error {
+	blackout, err := s.repo.GetByID(ctx, blackoutID)
+	if err != nil {
+		return notFound(err, ErrBlackoutNotFound)
+	}
+
+	if err := s.repo.Delete(ctx, blackoutID); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, model.AuditActionDelete, model.AuditEntityBlackout, blackout.ID, nil, blackout, nil)
+	return nil
+}
+
+// checkBlackout validates a blackout's timezone and date range.
+func checkBlackout(v *validation.Errors, blackout *model.Blackout) {
+	if _, ok := v.Location("timezone", blackout.Timezone); !ok {
+		return
+	}
+	start, err := time.Parse(validation.DateLayout, blackout.StartDate)
+	if err != nil {
+		v.Add("start_date", "must be a date such as 2006-01-02")
+	}
+	end, endErr := time.Parse(validation.DateLayout, blackout.EndDate)
+	if endErr != nil {
+		v.Add("end_date", "must be a date such as 2006-01-02")
+	}
+	if err == nil && endErr == nil && end.Before(start) {
+		v.Add("end_date", "must not be before start_date")
+	}
+}
+
+// blackoutWindow is the span a blackout covers: from midnight starting its
+// first day to midnight ending its last, in its timezone.
+func blackoutWindow(blackout model.Blackout) (timeWindow, error) {
+	loc, err := time.LoadLocation(blackout.Timezone)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	start, err := time.ParseInLocation(validation.DateLayout, blackout.StartDate, loc)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	end, err := time.ParseInLocation(validation.DateLayout, blackout.EndDate, loc)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	return timeWindow{start: start, end: end.AddDate(0, 0, 1)}, nil
+}
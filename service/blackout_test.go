@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBlackoutServiceSuite(t *testing.T) {
+	t.Run("Create_TrimsReasonAndRecordsAudit", func(t *testing.T) {
+		mockRepo := new(MockBlackoutRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := NewBlackoutService(mockRepo, mockAuditRepo)
+
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Blackout")).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+			return e.EntityType == model.AuditEntityBlackout && e.Action == model.AuditActionCreate
+		})).Return(nil)
+
+		blackout, err := svc.Create(context.Background(), model.CreateBlackoutRequest{
+			Email:     "alice@example.com",
+			StartDate: "2026-11-03",
+			EndDate:   "2026-11-14",
+			Timezone:  "Europe/Berlin",
+			Reason:    "  Annual leave ",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Annual leave", blackout.Reason)
+		mockRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create_EndBeforeStart", func(t *testing.T) {
+		mockRepo := new(MockBlackoutRepository)
+		svc := NewBlackoutService(mockRepo, nil)
+
+		_, err := svc.Create(context.Background(), model.CreateBlackoutRequest{
+			Email:     "alice@example.com",
+			StartDate: "2026-11-14",
+			EndDate:   "2026-11-03",
+			Timezone:  "UTC",
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, "end_date", apperr.From(err).Fields[0].Field)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Delete_NotFound", func(t *testing.T) {
+		mockRepo := new(MockBlackoutRepository)
+		svc := NewBlackoutService(mockRepo, nil)
+
+		id := uuid.New()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, apperr.ErrNotFound)
+
+		err := svc.Delete(context.Background(), id)
+
+		assert.ErrorIs(t, err, ErrBlackoutNotFound)
+	})
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/ram-ks/meeting-service/repository"
+)
+
+// checkAcceptingResponses rejects answers to a closed event, or to one
+// whose deadline has passed before the closer got to it.
+func checkAcceptingResponses(event *model.Event, now time.Time) error {
+	if event.RespondBy != nil && !now.Before(*event.RespondBy) {
+		return ErrResponsesClosed.WithMessage(fmt.Sprintf("event stopped accepting responses at %s", event.RespondBy.Format(time.RFC3339)))
+	}
+	if event.Status == model.EventStatusClosed {
+		return ErrResponsesClosed
+	}
+	return nil
+}
+
+// DeadlinePolicy configures a ResponseCloser. A zero ReminderLead sends no
+// reminders; AutoFinalizeThreshold is the percentage of participants who
+// must be able to attend the best slot, if_needed included, for an event
+// with auto_finalize to be finalized on it.
+type DeadlinePolicy struct {
+	Interval              time.Duration
+	ReminderLead          time.Duration
+	AutoFinalizeThreshold float64
+}
+
+// ResponseCloser enforces respond_by deadlines. It reminds pending
+// participants once as a deadline nears, closes events whose deadline has
+// passed and, for those with auto_finalize, finalizes the best slot if it
+// meets the threshold. Several instances can run one each; the repository
+// lets only one of them act on each event.
+type ResponseCloser struct {
+	eventRepo repository.EventRepository
+	events    EventService
+	scheduler SchedulerService
+	audit     auditRecorder
+	stream    streamPublisher
+	policy    DeadlinePolicy
+	now       func() time.Time
+}
+
+func NewResponseCloser(eventRepo repository.EventRepository, events EventService, scheduler SchedulerService, auditRepo repository.AuditRepository, publisher pubsub.Publisher, policy DeadlinePolicy) *ResponseCloser {
+	return &ResponseCloser{
+		eventRepo: eventRepo,
+		events:    events,
+		scheduler: scheduler,
+		audit:     auditRecorder{repo: auditRepo},
+		stream:    streamPublisher{pub: publisher},
+		policy:    policy,
+		now:       time.Now,
+	}
+}
+
+// Run checks deadlines once immediately and then on every tick until ctx is
+// cancelled.
+func (c *ResponseCloser) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.check(WithActor(ctx, systemActor))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ResponseCloser) check(ctx context.Context) {
+	now := c.now().UTC()
+
+	if c.policy.ReminderLead > 0 {
+		ids, err := c.eventRepo.ListDueReminders(ctx, now, now.Add(c.policy.ReminderLead))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to list events due a reminder", "error", err)
+		}
+		for _, id := range ids {
+			c.remind(ctx, id, now)
+		}
+	}
+
+	ids, err := c.eventRepo.ListPastDeadline(ctx, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list events past their deadline", "error", err)
+		return
+	}
+	for _, id := range ids {
+		c.close(ctx, id)
+	}
+}
+
+func (c *ResponseCloser) remind(ctx context.Context, eventID uuid.UUID, now time.Time) {
+	event, err := c.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load event to remind", "event_id", eventID, "error", err)
+		return
+	}
+	if event.Status != model.EventStatusOpen || event.RespondBy == nil {
+		return
+	}
+
+	marked, err := c.eventRepo.MarkReminded(ctx, eventID, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to mark event reminded", "event_id", eventID, "error", err)
+		return
+	}
+	if !marked {
+		return
+	}
+
+	pending := []uuid.UUID{}
+	for _, p := range event.Participants {
+		if p.Status == model.ParticipantStatusPending {
+			pending = append(pending, p.ID)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	c.stream.publish(ctx, model.StreamResponseReminder, eventID, model.ResponseReminderData{
+		RespondBy:      *event.RespondBy,
+		ParticipantIDs: pending,
+	})
+	slog.InfoContext(ctx, "reminded pending participants", "event_id", eventID, "count", len(pending))
+}
+
+func (c *ResponseCloser) close(ctx context.Context, eventID uuid.UUID) {
+	event, err := c.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load event to close", "event_id", eventID, "error", err)
+		return
+	}
+	if event.Status != model.EventStatusOpen || event.RespondBy == nil {
+		return
+	}
+
+	closed, err := c.eventRepo.CloseResponses(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to close event responses", "event_id", eventID, "error", err)
+		return
+	}
+	if !closed {
+		return
+	}
+
+	before := *event
+	event.Status = model.EventStatusClosed
+	c.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityEvent, eventID, &eventID, before, event)
+	c.stream.publish(ctx, model.StreamResponsesClosed, eventID, model.ResponsesClosedData{RespondBy: *event.RespondBy})
+	slog.InfoContext(ctx, "closed event responses", "event_id", eventID)
+
+	if event.AutoFinalize {
+		c.autoFinalize(ctx, eventID)
+	}
+}
+
+// autoFinalize finalizes the best slot if enough participants can attend
+// it, and otherwise leaves the closed event to the organizer.
+func (c *ResponseCloser) autoFinalize(ctx context.Context, eventID uuid.UUID) {
+	recs, err := c.scheduler.GetRecommendations(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to rank slots to auto-finalize", "event_id", eventID, "error", err)
+		return
+	}
+
+	var best *model.Recommendation
+	switch {
+	case len(recs.PerfectSlots) > 0:
+		best = &recs.PerfectSlots[0]
+	case len(recs.BestMatches) > 0:
+		best = &recs.BestMatches[0]
+	}
+	if best == nil || quorumPercent(*best) < c.policy.AutoFinalizeThreshold {
+		slog.InfoContext(ctx, "no slot meets the auto-finalize threshold", "event_id", eventID)
+		return
+	}
+
+	if _, err := c.events.FinalizeEvent(ctx, eventID, model.FinalizeEventRequest{SlotID: best.SlotID}); err != nil {
+		slog.ErrorContext(ctx, "failed to auto-finalize event", "event_id", eventID, "slot_id", best.SlotID, "error", err)
+	}
+}
+
+// quorumPercent is the percentage of participants who can attend a slot.
+// Unlike AvailabilityPercent it counts if_needed answers in full; they are
+// already part of AvailableCount.
+func quorumPercent(rec model.Recommendation) float64 {
+	if rec.TotalParticipants == 0 {
+		return 0
+	}
+	return float64(rec.AvailableCount) / float64(rec.TotalParticipants) * 100
+}
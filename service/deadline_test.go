@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResponseCloserSuite(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := DeadlinePolicy{Interval: time.Minute, ReminderLead: 24 * time.Hour, AutoFinalizeThreshold: 80}
+
+	eventID := uuid.New()
+	slotA, slotB := uuid.New(), uuid.New()
+	ana, bo := uuid.New(), uuid.New()
+	newEvent := func() *model.Event {
+		respondBy := now.Add(-time.Minute)
+		start := now.Add(48 * time.Hour)
+		return &model.Event{
+			ID:           eventID,
+			Status:       model.EventStatusOpen,
+			RespondBy:    &respondBy,
+			AutoFinalize: true,
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotA, EventID: eventID, StartTime: start, EndTime: start.Add(time.Hour)},
+				{ID: slotB, EventID: eventID, StartTime: start.Add(2 * time.Hour), EndTime: start.Add(3 * time.Hour)},
+			},
+			Participants: []model.Participant{
+				{ID: ana, Email: "ana@example.com", Status: model.ParticipantStatusResponded},
+				{ID: bo, Email: "bo@example.com", Status: model.ParticipantStatusPending},
+			},
+		}
+	}
+
+	// newCloserWithAudit wires a closer to a real event and scheduler
+	// service over the given repositories, with no reminders due and every
+	// audit write returning auditErr.
+	newCloserWithAudit := func(eventRepo *MockEventRepository, availRepo *MockAvailabilityRepository, hub *pubsub.Hub, auditErr error) *ResponseCloser {
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.Anything).Return(auditErr)
+		prefRepo := new(MockPreferredSlotRepository)
+		prefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+
+		events := NewEventService(eventRepo, auditRepo, hub, time.Hour)
+		scheduler := NewSchedulerService(eventRepo, availRepo, prefRepo, noBlackouts(), noWorkingHours())
+		closer := NewResponseCloser(eventRepo, events, scheduler, auditRepo, hub, policy)
+		closer.now = func() time.Time { return now }
+		eventRepo.On("ListDueReminders", mock.Anything, now, now.Add(policy.ReminderLead)).Return([]uuid.UUID{}, nil).Maybe()
+		return closer
+	}
+	newCloser := func(eventRepo *MockEventRepository, availRepo *MockAvailabilityRepository, hub *pubsub.Hub) *ResponseCloser {
+		return newCloserWithAudit(eventRepo, availRepo, hub, nil)
+	}
+	answers := func(statuses ...model.AvailabilityStatus) []model.Availability {
+		var out []model.Availability
+		for i, id := range []uuid.UUID{ana, bo}[:len(statuses)] {
+			out = append(out, model.Availability{EventID: eventID, ParticipantID: id, SlotID: slotA, Status: statuses[i]})
+		}
+		return out
+	}
+
+	t.Run("ClosesPastDeadlineAndAutoFinalizes", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		availRepo := new(MockAvailabilityRepository)
+		hub := pubsub.NewHub()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+		closer := newCloser(eventRepo, availRepo, hub)
+
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil).Once()
+		eventRepo.On("CloseResponses", mock.Anything, eventID).Return(true, nil)
+		closedEvent := newEvent()
+		closedEvent.Status = model.EventStatusClosed
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(closedEvent, nil)
+		availRepo.On("GetByEventID", mock.Anything, eventID).Return(answers(model.AvailabilityStatusAvailable, model.AvailabilityStatusAvailable), nil)
+		availRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil).Maybe()
+		eventRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *model.Event) bool {
+			return e.Status == model.EventStatusFinalized && *e.FinalizedSlotID == slotA
+		})).Return(nil)
+
+		closer.check(context.Background())
+
+		assert.Equal(t, model.StreamResponsesClosed, (<-sub.C).Type)
+		assert.Equal(t, model.StreamEventFinalized, (<-sub.C).Type)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("AuditWriteFails_StillClosesAndAutoFinalizes", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		availRepo := new(MockAvailabilityRepository)
+		hub := pubsub.NewHub()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+		closer := newCloserWithAudit(eventRepo, availRepo, hub, errors.New("connection reset"))
+
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil).Once()
+		eventRepo.On("CloseResponses", mock.Anything, eventID).Return(true, nil)
+		closedEvent := newEvent()
+		closedEvent.Status = model.EventStatusClosed
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(closedEvent, nil)
+		availRepo.On("GetByEventID", mock.Anything, eventID).Return(answers(model.AvailabilityStatusAvailable, model.AvailabilityStatusAvailable), nil)
+		availRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil).Maybe()
+		eventRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *model.Event) bool {
+			return e.Status == model.EventStatusFinalized && *e.FinalizedSlotID == slotA
+		})).Return(nil)
+
+		closer.check(context.Background())
+
+		assert.Equal(t, model.StreamResponsesClosed, (<-sub.C).Type)
+		assert.Equal(t, model.StreamEventFinalized, (<-sub.C).Type)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("LeavesEventClosedBelowThreshold", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		availRepo := new(MockAvailabilityRepository)
+		closer := newCloser(eventRepo, availRepo, pubsub.NewHub())
+
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+		eventRepo.On("CloseResponses", mock.Anything, eventID).Return(true, nil)
+		availRepo.On("GetByEventID", mock.Anything, eventID).Return(answers(model.AvailabilityStatusAvailable), nil)
+
+		closer.check(context.Background())
+
+		eventRepo.AssertCalled(t, "CloseResponses", mock.Anything, eventID)
+		eventRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CountsIfNeededTowardsQuorum", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		availRepo := new(MockAvailabilityRepository)
+		closer := newCloser(eventRepo, availRepo, pubsub.NewHub())
+
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil).Once()
+		eventRepo.On("CloseResponses", mock.Anything, eventID).Return(true, nil)
+		closedEvent := newEvent()
+		closedEvent.Status = model.EventStatusClosed
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(closedEvent, nil)
+		availRepo.On("GetByEventID", mock.Anything, eventID).Return(answers(model.AvailabilityStatusIfNeeded, model.AvailabilityStatusIfNeeded), nil)
+		availRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil).Maybe()
+		eventRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *model.Event) bool {
+			return e.Status == model.EventStatusFinalized && *e.FinalizedSlotID == slotA
+		})).Return(nil)
+
+		closer.check(context.Background())
+
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("SkipsEventClosedByAnotherInstance", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		hub := pubsub.NewHub()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+		closer := newCloser(eventRepo, new(MockAvailabilityRepository), hub)
+
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+		eventRepo.On("CloseResponses", mock.Anything, eventID).Return(false, nil)
+
+		closer.check(context.Background())
+
+		assert.Empty(t, sub.C)
+		eventRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RemindsPendingParticipantsOnce", func(t *testing.T) {
+		eventRepo := new(MockEventRepository)
+		hub := pubsub.NewHub()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+		closer := NewResponseCloser(eventRepo, nil, nil, nil, hub, policy)
+		closer.now = func() time.Time { return now }
+
+		event := newEvent()
+		respondBy := now.Add(6 * time.Hour)
+		event.RespondBy = &respondBy
+
+		eventRepo.On("ListDueReminders", mock.Anything, now, now.Add(policy.ReminderLead)).Return([]uuid.UUID{eventID}, nil)
+		eventRepo.On("ListPastDeadline", mock.Anything, now).Return([]uuid.UUID{}, nil)
+		eventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		eventRepo.On("MarkReminded", mock.Anything, eventID, now).Return(true, nil).Once()
+		eventRepo.On("MarkReminded", mock.Anything, eventID, now).Return(false, nil)
+
+		closer.check(context.Background())
+		closer.check(context.Background())
+
+		assert.Len(t, sub.C, 1)
+		msg := <-sub.C
+		assert.Equal(t, model.StreamResponseReminder, msg.Type)
+		assert.JSONEq(t, `{"respond_by":"2026-03-01T18:00:00Z","participant_ids":["`+bo.String()+`"]}`, string(msg.Data))
+	})
+}
+
+func TestCheckAcceptingResponses(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Second), now.Add(time.Hour)
+
+	assert.NoError(t, checkAcceptingResponses(&model.Event{Status: model.EventStatusOpen}, now))
+	assert.NoError(t, checkAcceptingResponses(&model.Event{Status: model.EventStatusOpen, RespondBy: &future}, now))
+
+	err := checkAcceptingResponses(&model.Event{Status: model.EventStatusOpen, RespondBy: &past}, now)
+	assert.ErrorIs(t, err, ErrResponsesClosed)
+	assert.EqualError(t, err, "event stopped accepting responses at 2026-03-01T11:59:59Z")
+
+	assert.ErrorIs(t, checkAcceptingResponses(&model.Event{Status: model.EventStatusClosed}, now), ErrResponsesClosed)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ram-ks/meeting-service/apperr"
+)
+
+var (
+	ErrEventNotFound         = apperr.New("event_not_found", http.StatusNotFound, "event not found")
+	ErrSlotNotFound          = apperr.New("slot_not_found", http.StatusNotFound, "slot not found")
+	ErrParticipantNotFound   = apperr.New("participant_not_found", http.StatusNotFound, "participant not found")
+	ErrAvailabilityNotFound  = apperr.New("availability_not_found", http.StatusNotFound, "availability not found")
+	ErrPreferredSlotNotFound = apperr.New("preferred_slot_not_found", http.StatusNotFound, "preferred slot not found")
+	ErrBlackoutNotFound      = apperr.New("blackout_not_found", http.StatusNotFound, "blackout not found")
+	ErrWorkingHoursNotFound  = apperr.New("working_hours_not_found", http.StatusNotFound, "working hours profile not found")
+	ErrPreferredSlotsExist   = apperr.New("preferred_slots_exist", http.StatusConflict, "preferred slots already exist for this email")
+	ErrInvalidStatus         = apperr.New("invalid_event_status", http.StatusConflict, "invalid event status for this operation")
+	ErrEventNotFlexible      = apperr.New("event_not_flexible", http.StatusConflict, "event has no search window")
+	ErrResponsesClosed       = apperr.New("responses_closed", http.StatusConflict, "event no longer accepts responses")
+	ErrSlotNotInEvent        = apperr.New("slot_not_in_event", http.StatusBadRequest, "slot does not belong to this event")
+	ErrRestoreWindowExpired  = apperr.New("restore_window_expired", http.StatusGone, "event can no longer be restored")
+)
+
+// notFound translates a repository not-found error into the service's more
+// specific one and passes any other failure through unchanged.
+func notFound(err error, target *apperr.Error) error {
+	if errors.Is(err, apperr.ErrNotFound) {
+		return target.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/metrics"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+type EventService interface {
+	CreateEvent(ctx context.Context, organizerID uuid.UUID, req model.CreateEventRequest) (*model.Event, error)
+	ListEvents(ctx context.Context, filter model.EventFilter) (*model.EventPage, error)
+	GetEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error)
+	UpdateEvent(ctx context.Context, eventID uuid.UUID, req model.UpdateEventRequest) (*model.Event, error)
+	DeleteEvent(ctx context.Context, eventID uuid.UUID) error
+	RestoreEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error)
+	PurgeDeletedEvents(ctx context.Context) (int, error)
+	FinalizeEvent(ctx context.Context, eventID uuid.UUID, req model.FinalizeEventRequest) (*model.Event, error)
+	GetHistory(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error)
+}
+
+type eventService struct {
+	eventRepo repository.EventRepository
+	auditRepo repository.AuditRepository
+	audit     auditRecorder
+	stream    streamPublisher
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewEventService creates the event service. Deleted events can be restored
+// for the retention period, after which PurgeDeletedEvents removes them.
+func NewEventService(eventRepo repository.EventRepository, auditRepo repository.AuditRepository, publisher pubsub.Publisher, retention time.Duration) EventService {
+	return &eventService{
+		eventRepo: eventRepo,
+		auditRepo: auditRepo,
+		audit:     auditRecorder{repo: auditRepo},
+		stream:    streamPublisher{pub: publisher},
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+func (s *eventService) CreateEvent(ctx context.Context, organizerID uuid.UUID, req model.CreateEventRequest) (*model.Event, error) {
+	now := time.Now().UTC()
+
+	event := &model.Event{
+		ID:           uuid.New(),
+		Title:        req.Title,
+		Description:  req.Description,
+		OrganizerID:  organizerID,
+		Duration:     req.Duration,
+		Status:       model.EventStatusOpen,
+		AutoFinalize: req.AutoFinalize,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	var v validation.Errors
+	for i, slotReq := range req.ProposedSlots {
+		times, ok := parseSlotTimes(&v, validation.Index("proposed_slots", i)+".", slotReq.StartTime, slotReq.EndTime, slotReq.Timezone)
+		if !ok {
+			continue
+		}
+
+		event.ProposedSlots = append(event.ProposedSlots, model.TimeSlot{
+			ID:        uuid.New(),
+			EventID:   event.ID,
+			StartTime: times.Start,
+			EndTime:   times.End,
+			Timezone:  times.Timezone,
+			CreatedAt: now,
+		})
+	}
+
+	if req.SearchWindow != nil {
+		if window, ok := parseSearchWindow(&v, "search_window.", *req.SearchWindow); ok {
+			event.SearchWindow = window
+			checkFlexibleEvent(&v, event.Duration, window)
+		}
+	} else if len(req.ProposedSlots) == 0 {
+		v.Add("proposed_slots", "must contain at least 1 item(s) unless search_window is set")
+	}
+	if req.RespondBy != "" {
+		event.RespondBy = parseRespondBy(&v, req.RespondBy, now)
+	}
+
+	checkUniqueEmails(&v, req.Participants)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, pReq := range req.Participants {
+		event.Participants = append(event.Participants, model.Participant{
+			ID:        uuid.New(),
+			EventID:   event.ID,
+			Email:     pReq.Email,
+			Name:      pReq.Name,
+			Status:    model.ParticipantStatusPending,
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	metrics.EventsCreated.Inc()
+	s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityEvent, event.ID, &event.ID, nil, event)
+	return event, nil
+}
+
+func (s *eventService) ListEvents(ctx context.Context, filter model.EventFilter) (*model.EventPage, error) {
+	return s.eventRepo.List(ctx, filter)
+}
+
+func (s *eventService) GetEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	return event, nil
+}
+
+func (s *eventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, req model.UpdateEventRequest) (*model.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	if event.Status == model.EventStatusFinalized || event.Status == model.EventStatusCancelled {
+		return nil, ErrInvalidStatus
+	}
+
+	before := *event
+
+	if req.Title != nil {
+		event.Title = *req.Title
+	}
+	if req.Description != nil {
+		event.Description = *req.Description
+	}
+	if req.Duration != nil {
+		event.Duration = *req.Duration
+	}
+
+	var v validation.Errors
+	if req.SearchWindow != nil {
+		event.SearchWindow, _ = parseSearchWindow(&v, "search_window.", *req.SearchWindow)
+	}
+	if req.RespondBy != nil {
+		event.RespondBy = nil
+		if *req.RespondBy != "" {
+			event.RespondBy = parseRespondBy(&v, *req.RespondBy, s.now().UTC())
+		}
+		// a new deadline gets its own reminder, and takes answers again
+		event.RemindedAt = nil
+		if event.Status == model.EventStatusClosed {
+			event.Status = model.EventStatusOpen
+		}
+	}
+	if req.AutoFinalize != nil {
+		event.AutoFinalize = *req.AutoFinalize
+	}
+	if v.Empty() {
+		checkFlexibleEvent(&v, event.Duration, event.SearchWindow)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Update(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityEvent, event.ID, &event.ID, before, event)
+	return event, nil
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, eventID uuid.UUID) error {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return notFound(err, ErrEventNotFound)
+	}
+
+	if err := s.eventRepo.Delete(ctx, eventID); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, model.AuditActionDelete, model.AuditEntityEvent, event.ID, &event.ID, event, nil)
+	return nil
+}
+
+func (s *eventService) RestoreEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.GetDeletedByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	if event.DeletedAt != nil && s.now().Sub(*event.DeletedAt) > s.retention {
+		return nil, ErrRestoreWindowExpired
+	}
+
+	if err := s.eventRepo.Restore(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	s.audit.record(ctx, model.AuditActionRestore, model.AuditEntityEvent, eventID, &eventID, event, restored)
+	return restored, nil
+}
+
+// PurgeDeletedEvents permanently removes events deleted longer ago than the
+// retention period and returns how many were removed.
+func (s *eventService) PurgeDeletedEvents(ctx context.Context) (int, error) {
+	ids, err := s.eventRepo.PurgeDeleted(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		eventID := id
+		s.audit.record(ctx, model.AuditActionPurge, model.AuditEntityEvent, eventID, &eventID, nil, nil)
+	}
+	if len(ids) > 0 {
+		slog.InfoContext(ctx, "purged deleted events", "count", len(ids))
+	}
+	return len(ids), nil
+}
+
+// FinalizeEvent settles an open event on one of its proposed slots.
+func (s *eventService) FinalizeEvent(ctx context.Context, eventID uuid.UUID, req model.FinalizeEventRequest) (*model.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	if event.Status == model.EventStatusFinalized || event.Status == model.EventStatusCancelled {
+		return nil, ErrInvalidStatus
+	}
+
+	slotFound := false
+	for _, slot := range event.ProposedSlots {
+		if slot.ID == req.SlotID {
+			slotFound = true
+			break
+		}
+	}
+	if !slotFound {
+		return nil, ErrSlotNotInEvent
+	}
+
+	before := *event
+	slotID := req.SlotID
+	event.Status = model.EventStatusFinalized
+	event.FinalizedSlotID = &slotID
+
+	if err := s.eventRepo.Update(ctx, event); err != nil {
+		return nil, err
+	}
+
+	metrics.EventsFinalized.Inc()
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityEvent, event.ID, &event.ID, before, event)
+	s.stream.publish(ctx, model.StreamEventFinalized, event.ID, model.EventFinalizedData{SlotID: slotID})
+	return event, nil
+}
+
+func (s *eventService) GetHistory(ctx context.Context, eventID uuid.UUID, limit int, cursor *model.AuditCursor) (*model.AuditPage, error) {
+	if _, err := s.eventRepo.GetByID(ctx, eventID); err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	return s.auditRepo.ListByEventID(ctx, eventID, limit, cursor)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventServiceSuite(t *testing.T) {
+	retention := 7 * 24 * time.Hour
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newService := func(eventRepo *MockEventRepository, auditRepo *MockAuditRepository) *eventService {
+		svc := NewEventService(eventRepo, auditRepo, nil, retention).(*eventService)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("RestoreEvent_WithinRetention", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := newService(mockEventRepo, mockAuditRepo)
+
+		eventID := uuid.New()
+		deletedAt := now.Add(-24 * time.Hour)
+
+		mockEventRepo.On("GetDeletedByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, DeletedAt: &deletedAt}, nil)
+		mockEventRepo.On("Restore", mock.Anything, eventID).Return(nil)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID}, nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+			return e.Action == model.AuditActionRestore && e.EntityID == eventID
+		})).Return(nil)
+
+		event, err := svc.RestoreEvent(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, event.DeletedAt)
+		mockEventRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("RestoreEvent_RetentionExpired", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		eventID := uuid.New()
+		deletedAt := now.Add(-retention - time.Minute)
+
+		mockEventRepo.On("GetDeletedByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, DeletedAt: &deletedAt}, nil)
+
+		event, err := svc.RestoreEvent(context.Background(), eventID)
+
+		assert.Nil(t, event)
+		assert.ErrorIs(t, err, ErrRestoreWindowExpired)
+		mockEventRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RestoreEvent_NotDeleted", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		eventID := uuid.New()
+		mockEventRepo.On("GetDeletedByID", mock.Anything, eventID).Return(nil, apperr.ErrNotFound)
+
+		_, err := svc.RestoreEvent(context.Background(), eventID)
+
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	t.Run("PurgeDeletedEvents_UsesRetentionCutoff", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := newService(mockEventRepo, mockAuditRepo)
+
+		purged := []uuid.UUID{uuid.New(), uuid.New()}
+		mockEventRepo.On("PurgeDeleted", mock.Anything, now.Add(-retention)).Return(purged, nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+			return e.Action == model.AuditActionPurge && e.Actor == "system"
+		})).Return(nil).Twice()
+
+		count, err := svc.PurgeDeletedEvents(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		mockEventRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("CreateEvent_ReportsEveryInvalidField", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		_, err := svc.CreateEvent(context.Background(), uuid.New(), model.CreateEventRequest{
+			Title:    "Planning",
+			Duration: "1h",
+			ProposedSlots: []model.CreateSlotRequest{
+				{StartTime: "2026-03-02T10:00:00", EndTime: "2026-03-02T11:00:00", Timezone: "Europe/Berlin"},
+				{StartTime: "2026-03-02T11:00:00", EndTime: "2026-03-02T10:00:00", Timezone: "Europe/Berlin"},
+				{StartTime: "2026-03-02T10:00:00", EndTime: "2026-03-02T11:00:00", Timezone: "Mars/Olympus"},
+				{StartTime: "tomorrow", EndTime: "2026-03-02T11:00:00", Timezone: "UTC"},
+			},
+			Participants: []model.CreateParticipantRequest{
+				{Email: "ana@example.com", Name: "Ana"},
+				{Email: "bo@example.com", Name: "Bo"},
+				{Email: "Ana@Example.com", Name: "Ana again"},
+			},
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "proposed_slots[1].end_time", Message: "must be after start_time"},
+			{Field: "proposed_slots[2].timezone", Message: "must be a valid IANA timezone"},
+			{Field: "proposed_slots[3].start_time", Message: "must be a timestamp such as 2006-01-02T15:04:05"},
+			{Field: "participants[2].email", Message: "duplicates participants[0].email"},
+		}, apperr.From(err).Fields)
+		mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CreateEvent_FlexibleWithoutProposedSlots", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := newService(mockEventRepo, mockAuditRepo)
+
+		mockEventRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Event")).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		event, err := svc.CreateEvent(context.Background(), uuid.New(), model.CreateEventRequest{
+			Title:        "Planning",
+			Duration:     "45m",
+			SearchWindow: &model.SearchWindowRequest{Start: "2026-03-02T09:00:00+01:00", End: "2026-03-06T17:00:00+01:00"},
+			Participants: []model.CreateParticipantRequest{{Email: "ana@example.com", Name: "Ana"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, event.ProposedSlots)
+		assert.Equal(t, time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), event.SearchWindow.Start)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("CreateEvent_FlexibleNeedsParsableDuration", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		_, err := svc.CreateEvent(context.Background(), uuid.New(), model.CreateEventRequest{
+			Title:        "Planning",
+			Duration:     "an hour or so",
+			SearchWindow: &model.SearchWindowRequest{Start: "2026-03-02T09:00:00Z", End: "2026-03-02T17:00:00Z"},
+			Participants: []model.CreateParticipantRequest{{Email: "ana@example.com", Name: "Ana"}},
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, "duration", apperr.From(err).Fields[0].Field)
+		mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CreateEvent_RespondByMustBeAhead", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		_, err := svc.CreateEvent(context.Background(), uuid.New(), model.CreateEventRequest{
+			Title:         "Planning",
+			Duration:      "1h",
+			ProposedSlots: []model.CreateSlotRequest{{StartTime: "2026-03-02T10:00:00", EndTime: "2026-03-02T11:00:00", Timezone: "UTC"}},
+			Participants:  []model.CreateParticipantRequest{{Email: "ana@example.com", Name: "Ana"}},
+			RespondBy:     "2020-01-01T00:00:00Z",
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{{Field: "respond_by", Message: "must be in the future"}}, apperr.From(err).Fields)
+		mockEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UpdateEvent_NewDeadlineReopensClosedEvent", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := newService(mockEventRepo, mockAuditRepo)
+
+		eventID := uuid.New()
+		passed := now.Add(-time.Hour)
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{
+			ID:         eventID,
+			Status:     model.EventStatusClosed,
+			RespondBy:  &passed,
+			RemindedAt: &passed,
+		}, nil)
+		mockEventRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Event")).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		respondBy := "2026-03-05T12:00:00+01:00"
+		event, err := svc.UpdateEvent(context.Background(), eventID, model.UpdateEventRequest{RespondBy: &respondBy})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.EventStatusOpen, event.Status)
+		assert.Equal(t, time.Date(2026, 3, 5, 11, 0, 0, 0, time.UTC), *event.RespondBy)
+		assert.Nil(t, event.RemindedAt)
+	})
+
+	t.Run("CreateEvent_NeedsSlotsOrSearchWindow", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		_, err := svc.CreateEvent(context.Background(), uuid.New(), model.CreateEventRequest{
+			Title:         "Planning",
+			Duration:      "1h",
+			ProposedSlots: []model.CreateSlotRequest{},
+			Participants:  []model.CreateParticipantRequest{{Email: "ana@example.com", Name: "Ana"}},
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, "proposed_slots", apperr.From(err).Fields[0].Field)
+	})
+
+	t.Run("FinalizeEvent_PublishesToStream", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		hub := pubsub.NewHub()
+		svc := NewEventService(mockEventRepo, mockAuditRepo, hub, retention)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{
+			ID:            eventID,
+			Status:        model.EventStatusOpen,
+			ProposedSlots: []model.TimeSlot{{ID: slotID, EventID: eventID}},
+		}, nil)
+		mockEventRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *model.Event) bool {
+			return e.Status == model.EventStatusFinalized && *e.FinalizedSlotID == slotID
+		})).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		event, err := svc.FinalizeEvent(context.Background(), eventID, model.FinalizeEventRequest{SlotID: slotID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.EventStatusFinalized, event.Status)
+
+		msg := <-sub.C
+		assert.Equal(t, model.StreamEventFinalized, msg.Type)
+		assert.JSONEq(t, `{"slot_id":"`+slotID.String()+`"}`, string(msg.Data))
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("FinalizeEvent_RejectsForeignSlot", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := newService(mockEventRepo, new(MockAuditRepository))
+
+		eventID := uuid.New()
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(&model.Event{ID: eventID, Status: model.EventStatusOpen}, nil)
+
+		_, err := svc.FinalizeEvent(context.Background(), eventID, model.FinalizeEventRequest{SlotID: uuid.New()})
+
+		assert.ErrorIs(t, err, ErrSlotNotInEvent)
+		mockEventRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
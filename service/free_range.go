@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+// FreeRangeService handles flexible events, whose participants paint the
+// ranges they are free within the event's search window rather than
+// answering proposed slots.
+type FreeRangeService interface {
+	SubmitFreeRanges(ctx context.Context, eventID uuid.UUID, req model.SubmitFreeRangesRequest) ([]model.FreeRange, error)
+	GetFreeRanges(ctx context.Context, eventID uuid.UUID) ([]model.FreeRange, error)
+	GetFreeWindows(ctx context.Context, eventID uuid.UUID) (*model.FreeWindowResponse, error)
+	PromoteWindow(ctx context.Context, eventID uuid.UUID, req model.PromoteWindowRequest) (*model.TimeSlot, error)
+}
+
+type freeRangeService struct {
+	repo             repository.FreeRangeRepository
+	eventRepo        repository.EventRepository
+	blackoutRepo     repository.BlackoutRepository
+	workingHoursRepo repository.WorkingHoursRepository
+	audit            auditRecorder
+	stream           streamPublisher
+}
+
+func NewFreeRangeService(repo repository.FreeRangeRepository, eventRepo repository.EventRepository, blackoutRepo repository.BlackoutRepository, workingHoursRepo repository.WorkingHoursRepository, auditRepo repository.AuditRepository, publisher pubsub.Publisher) FreeRangeService {
+	return &freeRangeService{
+		repo:             repo,
+		eventRepo:        eventRepo,
+		blackoutRepo:     blackoutRepo,
+		workingHoursRepo: workingHoursRepo,
+		audit:            auditRecorder{repo: auditRepo},
+		stream:           streamPublisher{pub: publisher},
+	}
+}
+
+// SubmitFreeRanges replaces a participant's ranges. Ranges must lie inside
+// the search window; overlapping or touching ones are merged.
+func (s *freeRangeService) SubmitFreeRanges(ctx context.Context, eventID uuid.UUID, req model.SubmitFreeRangesRequest) ([]model.FreeRange, error) {
+	event, err := s.flexibleEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var participant *model.Participant
+	for i, p := range event.Participants {
+		if p.ID == req.ParticipantID {
+			participant = &event.Participants[i]
+			break
+		}
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	if err := checkAcceptingResponses(event, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	window := event.SearchWindow
+	var v validation.Errors
+	spans := make([]timeWindow, 0, len(req.Ranges))
+	for i, r := range req.Ranges {
+		prefix := validation.Index("ranges", i) + "."
+		start, startOK := v.RFC3339(prefix+"start_time", r.StartTime)
+		end, endOK := v.RFC3339(prefix+"end_time", r.EndTime)
+		if !startOK || !endOK || !v.Range(prefix+"end_time", "start_time", start, end) {
+			continue
+		}
+		if start.Before(window.Start) || end.After(window.End) {
+			v.Add(prefix+"start_time", "must lie inside the event's search window")
+			continue
+		}
+		spans = append(spans, timeWindow{start: start.UTC(), end: end.UTC()})
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.participantRanges(ctx, eventID, req.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	merged := mergeWindows(spans)
+	ranges := make([]model.FreeRange, 0, len(merged))
+	for _, w := range merged {
+		ranges = append(ranges, model.FreeRange{
+			ID:            uuid.New(),
+			EventID:       eventID,
+			ParticipantID: req.ParticipantID,
+			StartTime:     w.start,
+			EndTime:       w.end,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := s.repo.ReplaceForParticipant(ctx, eventID, req.ParticipantID, ranges); err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityFreeRanges, req.ParticipantID, &eventID, existing, ranges)
+	s.stream.publish(ctx, model.StreamFreeRangesSubmitted, eventID, model.FreeRangesSubmittedData{
+		ParticipantID: req.ParticipantID,
+		Ranges:        ranges,
+	})
+
+	if participant.Status != model.ParticipantStatusResponded {
+		if err := s.eventRepo.UpdateParticipantStatus(ctx, participant.ID, model.ParticipantStatusResponded); err != nil {
+			return nil, err
+		}
+		before := *participant
+		participant.Status = model.ParticipantStatusResponded
+		s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityParticipant, participant.ID, &eventID, before, participant)
+		s.stream.publish(ctx, model.StreamParticipantStatusChanged, eventID, model.ParticipantStatusChangedData{
+			ParticipantID: participant.ID,
+			From:          before.Status,
+			To:            participant.Status,
+		})
+	}
+	return ranges, nil
+}
+
+func (s *freeRangeService) GetFreeRanges(ctx context.Context, eventID uuid.UUID) ([]model.FreeRange, error) {
+	if _, err := s.flexibleEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByEventID(ctx, eventID)
+}
+
+// GetFreeWindows finds every window of at least the event's duration in
+// which the most participants are free. Like answers to proposed slots,
+// painted ranges don't count where they fall in a blackout or outside the
+// participant's hard limits.
+func (s *freeRangeService) GetFreeWindows(ctx context.Context, eventID uuid.UUID) (*model.FreeWindowResponse, error) {
+	event, err := s.flexibleEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := eventDuration(event)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := s.repo.GetByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	search := timeWindow{start: event.SearchWindow.Start, end: event.SearchWindow.End}
+	emails := participantEmails(event.Participants)
+	blackouts, err := blackoutsByEmail(ctx, s.blackoutRepo, emails, search)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := hoursProfilesByEmail(ctx, s.workingHoursRepo, emails)
+	if err != nil {
+		return nil, err
+	}
+	ranges = usableRanges(event.Participants, ranges, search, blackouts, profiles)
+
+	participants := make([]uuid.UUID, len(event.Participants))
+	for i, p := range event.Participants {
+		participants[i] = p.ID
+	}
+
+	return &model.FreeWindowResponse{
+		EventID:  eventID,
+		Duration: event.Duration,
+		Windows:  findFreeWindows(*event.SearchWindow, duration, participants, ranges),
+	}, nil
+}
+
+// PromoteWindow adds a proposed slot taken from a free window, so the
+// event can be finalized on it.
+func (s *freeRangeService) PromoteWindow(ctx context.Context, eventID uuid.UUID, req model.PromoteWindowRequest) (*model.TimeSlot, error) {
+	event, err := s.flexibleEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == model.EventStatusFinalized || event.Status == model.EventStatusCancelled {
+		return nil, ErrInvalidStatus
+	}
+	duration, err := eventDuration(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var v validation.Errors
+	times, ok := parseSlotTimes(&v, "", req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return nil, v.Err()
+	}
+	if times.Start.Before(event.SearchWindow.Start) || times.End.After(event.SearchWindow.End) {
+		v.Add("start_time", "must lie inside the event's search window")
+	} else if times.End.Sub(times.Start) < duration {
+		v.Add("end_time", "must be at least the event's duration of %s after start_time", event.Duration)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	// the scheduler rejects slots crossing anyone's hard limits, so don't
+	// create one
+	profiles, err := hoursProfilesByEmail(ctx, s.workingHoursRepo, participantEmails(event.Participants))
+	if err != nil {
+		return nil, err
+	}
+	candidate := model.TimeSlot{StartTime: times.Start, EndTime: times.End}
+	for _, p := range event.Participants {
+		profile, ok := profiles[strings.ToLower(p.Email)]
+		if ok && profile.check(candidate) == model.HoursViolationHardLimits {
+			v.Add("start_time", "crosses the hard limits of %s's working hours", p.Name)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	slot := &model.TimeSlot{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		StartTime: times.Start,
+		EndTime:   times.End,
+		Timezone:  times.Timezone,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.eventRepo.CreateSlot(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, model.AuditActionCreate, model.AuditEntitySlot, slot.ID, &eventID, nil, slot)
+	return slot, nil
+}
+
+func (s *freeRangeService) flexibleEvent(ctx context.Context, eventID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	if event.SearchWindow == nil {
+		return nil, ErrEventNotFlexible
+	}
+	return event, nil
+}
+
+func (s *freeRangeService) participantRanges(ctx context.Context, eventID, participantID uuid.UUID) ([]model.FreeRange, error) {
+	all, err := s.repo.GetByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	var ranges []model.FreeRange
+	for _, r := range all {
+		if r.ParticipantID == participantID {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges, nil
+}
+
+// usableRanges cuts from each participant's ranges the parts in one of
+// their blackouts or outside their hard limits.
+func usableRanges(participants []model.Participant, ranges []model.FreeRange, search timeWindow, blackouts blackoutIndex, profiles map[string]hoursProfile) []model.FreeRange {
+	byParticipant := make(map[uuid.UUID][]timeWindow)
+	for _, r := range ranges {
+		byParticipant[r.ParticipantID] = append(byParticipant[r.ParticipantID], timeWindow{start: r.StartTime, end: r.EndTime})
+	}
+
+	var usable []model.FreeRange
+	for _, p := range participants {
+		free := mergeWindows(byParticipant[p.ID])
+		email := strings.ToLower(p.Email)
+		if profile, ok := profiles[email]; ok {
+			free = intersectWindows(free, profile.allowedWindows(search))
+		}
+		var away []timeWindow
+		for _, b := range blackouts[email] {
+			away = append(away, b.window)
+		}
+		free = subtractWindows(free, mergeWindows(away))
+
+		for _, w := range free {
+			usable = append(usable, model.FreeRange{ParticipantID: p.ID, StartTime: w.start.UTC(), EndTime: w.end.UTC()})
+		}
+	}
+	return usable
+}
+
+func participantEmails(participants []model.Participant) []string {
+	emails := make([]string, 0, len(participants))
+	for _, p := range participants {
+		emails = append(emails, p.Email)
+	}
+	return emails
+}
+
+// eventDuration parses a flexible event's duration, which is checked when
+// the search window is set.
+func eventDuration(event *model.Event) (time.Duration, error) {
+	d, err := time.ParseDuration(event.Duration)
+	if err != nil || d <= 0 {
+		return 0, apperr.BadRequest(fmt.Sprintf("event duration %q is not a duration such as 30m or 1h30m", event.Duration))
+	}
+	return d, nil
+}
+
+// mergeWindows sorts windows and joins those that overlap or touch.
+func mergeWindows(windows []timeWindow) []timeWindow {
+	sort.Slice(windows, func(i, j int) bool { return windows[i].start.Before(windows[j].start) })
+	var merged []timeWindow
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w.start.After(merged[n-1].end) {
+			if w.end.After(merged[n-1].end) {
+				merged[n-1].end = w.end
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// intersectWindows returns the parts of a that lie in b; both must be
+// merged.
+func intersectWindows(a, b []timeWindow) []timeWindow {
+	var out []timeWindow
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		w := timeWindow{start: latest(a[i].start, b[j].start), end: earliest(a[i].end, b[j].end)}
+		if w.end.After(w.start) {
+			out = append(out, w)
+		}
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// subtractWindows returns the parts of windows outside cut; both must be
+// merged.
+func subtractWindows(windows, cut []timeWindow) []timeWindow {
+	var out []timeWindow
+	j := 0
+	for _, w := range windows {
+		for j < len(cut) && !cut[j].end.After(w.start) {
+			j++
+		}
+		start := w.start
+		for k := j; k < len(cut) && cut[k].start.Before(w.end); k++ {
+			if cut[k].start.After(start) {
+				out = append(out, timeWindow{start: start, end: cut[k].start})
+			}
+			start = latest(start, cut[k].end)
+		}
+		if w.end.After(start) {
+			out = append(out, timeWindow{start: start, end: w.end})
+		}
+	}
+	return out
+}
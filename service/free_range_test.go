@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFreeRangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockFreeRangeRepository) ReplaceForParticipant(ctx context.Context, eventID, participantID uuid.UUID, ranges []model.FreeRange) error {
+	args := m.Called(ctx, eventID, participantID, ranges)
+	return args.Error(0)
+}
+
+func (m *MockFreeRangeRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.FreeRange, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FreeRange), args.Error(1)
+}
+
+func TestFindFreeWindows(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	window := model.SearchWindow{Start: at(8, 0), End: at(18, 0)}
+
+	ana, bo, cy := uuid.New(), uuid.New(), uuid.New()
+	participants := []uuid.UUID{ana, bo, cy}
+	free := func(id uuid.UUID, from, to time.Time) model.FreeRange {
+		return model.FreeRange{ParticipantID: id, StartTime: from, EndTime: to}
+	}
+
+	t.Run("FindsEveryWindowWhereMostAreFree", func(t *testing.T) {
+		ranges := []model.FreeRange{
+			free(ana, at(9, 0), at(12, 0)),
+			free(ana, at(14, 0), at(17, 0)),
+			free(bo, at(10, 0), at(11, 30)),
+			free(bo, at(15, 0), at(16, 0)),
+			free(cy, at(10, 30), at(16, 30)),
+		}
+
+		windows := findFreeWindows(window, time.Hour, participants, ranges)
+
+		// everyone is free only 10:30-11:30 and 15:00-16:00
+		assert.Len(t, windows, 2)
+		assert.Equal(t, at(10, 30), windows[0].StartTime)
+		assert.Equal(t, at(11, 30), windows[0].EndTime)
+		assert.Equal(t, at(15, 0), windows[1].StartTime)
+		assert.Equal(t, at(16, 0), windows[1].EndTime)
+		assert.Equal(t, 3, windows[1].FreeCount)
+		assert.Equal(t, float64(100), windows[1].AvailabilityPercent)
+		assert.ElementsMatch(t, participants, windows[1].ParticipantIDs)
+	})
+
+	t.Run("FallsBackToFewerParticipantsWhenWindowsAreTooShort", func(t *testing.T) {
+		ranges := []model.FreeRange{
+			free(ana, at(9, 0), at(12, 0)),
+			free(bo, at(10, 0), at(10, 30)),
+			free(cy, at(9, 30), at(11, 0)),
+		}
+
+		windows := findFreeWindows(window, time.Hour, participants, ranges)
+
+		// all three overlap for only half an hour; ana and cy manage 90
+		// minutes, reported as one window rather than one per start
+		assert.Len(t, windows, 1)
+		assert.Equal(t, at(9, 30), windows[0].StartTime)
+		assert.Equal(t, at(11, 0), windows[0].EndTime)
+		assert.Equal(t, 2, windows[0].FreeCount)
+		assert.ElementsMatch(t, []uuid.UUID{ana, cy}, windows[0].ParticipantIDs)
+	})
+
+	t.Run("ClipsToSearchWindowAndIgnoresRemovedParticipants", func(t *testing.T) {
+		ranges := []model.FreeRange{
+			free(ana, at(7, 0), at(9, 0)),
+			free(bo, at(6, 0), at(8, 45)),
+			free(uuid.New(), at(8, 0), at(9, 0)),
+		}
+
+		windows := findFreeWindows(window, 30*time.Minute, participants, ranges)
+
+		assert.Len(t, windows, 1)
+		assert.Equal(t, at(8, 0), windows[0].StartTime)
+		assert.Equal(t, at(8, 45), windows[0].EndTime)
+		assert.Equal(t, 2, windows[0].FreeCount)
+	})
+
+	t.Run("NoOneFreeLongEnough", func(t *testing.T) {
+		ranges := []model.FreeRange{free(ana, at(9, 0), at(9, 45))}
+
+		windows := findFreeWindows(window, time.Hour, participants, ranges)
+
+		assert.Empty(t, windows)
+	})
+}
+
+func TestUsableRanges(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+	ana := model.Participant{ID: uuid.New(), Email: "Ana@example.com"}
+	bo := model.Participant{ID: uuid.New(), Email: "bo@example.com"}
+	search := timeWindow{start: at(2, 8), end: at(4, 8)}
+
+	notBefore, notAfter := "08:00", "18:00"
+	profile, err := newHoursProfile(model.WorkingHoursProfile{Timezone: "Europe/Berlin", NotBefore: &notBefore, NotAfter: &notAfter})
+	assert.NoError(t, err)
+	blackout := model.Blackout{Email: "bo@example.com", StartDate: "2026-03-03", EndDate: "2026-03-03", Timezone: "UTC"}
+	away, err := blackoutWindow(blackout)
+	assert.NoError(t, err)
+
+	ranges := []model.FreeRange{
+		{ParticipantID: ana.ID, StartTime: at(2, 9), EndTime: at(3, 20)},
+		{ParticipantID: bo.ID, StartTime: at(2, 10), EndTime: at(3, 12)},
+	}
+
+	usable := usableRanges([]model.Participant{ana, bo}, ranges, search,
+		blackoutIndex{"bo@example.com": {{blackout: blackout, window: away}}},
+		map[string]hoursProfile{"ana@example.com": profile})
+
+	// ana only between 08:00 and 18:00 Berlin time, bo not on the 3rd
+	assert.Equal(t, []model.FreeRange{
+		{ParticipantID: ana.ID, StartTime: at(2, 9), EndTime: at(2, 17)},
+		{ParticipantID: ana.ID, StartTime: at(3, 7), EndTime: at(3, 17)},
+		{ParticipantID: bo.ID, StartTime: at(2, 10), EndTime: at(3, 0)},
+	}, usable)
+}
+
+func TestFreeRangeServiceSuite(t *testing.T) {
+	eventID := uuid.New()
+	participantID := uuid.New()
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	newEvent := func() *model.Event {
+		return &model.Event{
+			ID:           eventID,
+			Duration:     "1h",
+			Status:       model.EventStatusOpen,
+			SearchWindow: &model.SearchWindow{Start: start, End: start.Add(10 * time.Hour)},
+			Participants: []model.Participant{{ID: participantID, Email: "ana@example.com", Status: model.ParticipantStatusPending}},
+		}
+	}
+
+	t.Run("SubmitFreeRanges_MergesOverlappingRanges", func(t *testing.T) {
+		mockRepo := new(MockFreeRangeRepository)
+		mockEventRepo := new(MockEventRepository)
+		hub := pubsub.NewHub()
+		sub := hub.Subscribe(eventID)
+		defer sub.Close()
+		svc := NewFreeRangeService(mockRepo, mockEventRepo, noBlackouts(), noWorkingHours(), nil, hub)
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+		mockEventRepo.On("UpdateParticipantStatus", mock.Anything, participantID, model.ParticipantStatusResponded).Return(nil)
+		mockRepo.On("GetByEventID", mock.Anything, eventID).Return([]model.FreeRange{}, nil)
+		mockRepo.On("ReplaceForParticipant", mock.Anything, eventID, participantID, mock.Anything).Return(nil)
+
+		ranges, err := svc.SubmitFreeRanges(context.Background(), eventID, model.SubmitFreeRangesRequest{
+			ParticipantID: participantID,
+			Ranges: []model.FreeRangeRequest{
+				{StartTime: "2026-03-02T14:00:00Z", EndTime: "2026-03-02T15:00:00Z"},
+				{StartTime: "2026-03-02T09:00:00Z", EndTime: "2026-03-02T10:30:00Z"},
+				{StartTime: "2026-03-02T11:00:00+01:00", EndTime: "2026-03-02T11:00:00Z"},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, ranges, 2)
+		assert.Equal(t, start.Add(time.Hour), ranges[0].StartTime)
+		assert.Equal(t, start.Add(3*time.Hour), ranges[0].EndTime)
+		assert.Equal(t, start.Add(6*time.Hour), ranges[1].StartTime)
+		assert.Equal(t, model.StreamFreeRangesSubmitted, (<-sub.C).Type)
+		assert.Equal(t, model.StreamParticipantStatusChanged, (<-sub.C).Type)
+		mockRepo.AssertExpectations(t)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("SubmitFreeRanges_RejectsRangesOutsideSearchWindow", func(t *testing.T) {
+		mockRepo := new(MockFreeRangeRepository)
+		mockEventRepo := new(MockEventRepository)
+		svc := NewFreeRangeService(mockRepo, mockEventRepo, noBlackouts(), noWorkingHours(), nil, nil)
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+
+		_, err := svc.SubmitFreeRanges(context.Background(), eventID, model.SubmitFreeRangesRequest{
+			ParticipantID: participantID,
+			Ranges: []model.FreeRangeRequest{
+				{StartTime: "2026-03-02T07:00:00Z", EndTime: "2026-03-02T09:00:00Z"},
+				{StartTime: "2026-03-02T10:00:00Z", EndTime: "2026-03-02T09:00:00Z"},
+			},
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "ranges[0].start_time", Message: "must lie inside the event's search window"},
+			{Field: "ranges[1].end_time", Message: "must be after start_time"},
+		}, apperr.From(err).Fields)
+		mockRepo.AssertNotCalled(t, "ReplaceForParticipant", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubmitFreeRanges_EventNotFlexible", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := NewFreeRangeService(new(MockFreeRangeRepository), mockEventRepo, noBlackouts(), noWorkingHours(), nil, nil)
+
+		event := newEvent()
+		event.SearchWindow = nil
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+
+		_, err := svc.SubmitFreeRanges(context.Background(), eventID, model.SubmitFreeRangesRequest{ParticipantID: participantID})
+
+		assert.ErrorIs(t, err, ErrEventNotFlexible)
+	})
+
+	t.Run("PromoteWindow_CreatesSlot", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := NewFreeRangeService(new(MockFreeRangeRepository), mockEventRepo, noBlackouts(), noWorkingHours(), nil, nil)
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+		mockEventRepo.On("CreateSlot", mock.Anything, mock.AnythingOfType("*model.TimeSlot")).Return(nil)
+
+		slot, err := svc.PromoteWindow(context.Background(), eventID, model.PromoteWindowRequest{
+			StartTime: "2026-03-02T10:30:00Z",
+			EndTime:   "2026-03-02T11:30:00Z",
+			Timezone:  "Europe/Berlin",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, eventID, slot.EventID)
+		assert.Equal(t, start.Add(150*time.Minute), slot.StartTime)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("PromoteWindow_CrossesHardLimits", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		hoursRepo := new(MockWorkingHoursRepository)
+		svc := NewFreeRangeService(new(MockFreeRangeRepository), mockEventRepo, noBlackouts(), hoursRepo, nil, nil)
+
+		event := newEvent()
+		event.Participants[0].Name = "Ana"
+		notAfter := "18:00"
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		hoursRepo.On("GetByEmails", mock.Anything, []string{"ana@example.com"}).Return([]model.WorkingHoursProfile{
+			{Email: "ana@example.com", Timezone: "Europe/Berlin", NotAfter: &notAfter},
+		}, nil)
+
+		_, err := svc.PromoteWindow(context.Background(), eventID, model.PromoteWindowRequest{
+			StartTime: "2026-03-02T16:30:00",
+			EndTime:   "2026-03-02T17:30:00",
+			Timezone:  "UTC",
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{{Field: "start_time", Message: "crosses the hard limits of Ana's working hours"}}, apperr.From(err).Fields)
+		mockEventRepo.AssertNotCalled(t, "CreateSlot", mock.Anything, mock.Anything)
+	})
+
+	t.Run("PromoteWindow_ShorterThanDuration", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		svc := NewFreeRangeService(new(MockFreeRangeRepository), mockEventRepo, noBlackouts(), noWorkingHours(), nil, nil)
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(newEvent(), nil)
+
+		_, err := svc.PromoteWindow(context.Background(), eventID, model.PromoteWindowRequest{
+			StartTime: "2026-03-02T10:30:00Z",
+			EndTime:   "2026-03-02T11:00:00Z",
+			Timezone:  "UTC",
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, "end_time", apperr.From(err).Fields[0].Field)
+		mockEventRepo.AssertNotCalled(t, "CreateSlot", mock.Anything, mock.Anything)
+	})
+}
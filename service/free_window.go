@@ -0,0 +1,177 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// freeSegment is a stretch of the search window over which the same
+// participants, by index, are free.
+type freeSegment struct {
+	start time.Time
+	end   time.Time
+	free  []int
+}
+
+type rangeEdge struct {
+	at          time.Time
+	participant int
+	delta       int
+}
+
+// freeSegments sweeps across the search window, opening a participant at
+// the start of each of their ranges and closing them at its end, and cuts
+// the window wherever the set of free participants changes. Ranges of
+// participants no longer in the event are ignored.
+func freeSegments(window model.SearchWindow, participants []uuid.UUID, ranges []model.FreeRange) []freeSegment {
+	index := make(map[uuid.UUID]int, len(participants))
+	for i, id := range participants {
+		index[id] = i
+	}
+
+	edges := make([]rangeEdge, 0, 2*len(ranges))
+	for _, r := range ranges {
+		i, ok := index[r.ParticipantID]
+		if !ok {
+			continue
+		}
+		start, end := r.StartTime, r.EndTime
+		if start.Before(window.Start) {
+			start = window.Start
+		}
+		if end.After(window.End) {
+			end = window.End
+		}
+		if !end.After(start) {
+			continue
+		}
+		edges = append(edges, rangeEdge{at: start, participant: i, delta: 1}, rangeEdge{at: end, participant: i, delta: -1})
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].at.Before(edges[j].at) })
+
+	// depth rather than a flag, so overlapping ranges of one participant
+	// can't close each other early
+	depth := make([]int, len(participants))
+	var segments []freeSegment
+	cut := func(start, end time.Time) {
+		if !end.After(start) {
+			return
+		}
+		var free []int
+		for i, d := range depth {
+			if d > 0 {
+				free = append(free, i)
+			}
+		}
+		segments = append(segments, freeSegment{start: start, end: end, free: free})
+	}
+
+	at := window.Start
+	for i := 0; i < len(edges); {
+		t := edges[i].at
+		cut(at, t)
+		for ; i < len(edges) && edges[i].at.Equal(t); i++ {
+			depth[edges[i].participant] += edges[i].delta
+		}
+		at = t
+	}
+	cut(at, window.End)
+	return segments
+}
+
+// findFreeWindows returns every window of at least duration in which the
+// most participants are free throughout, earliest first. A window reaches
+// as far as its participants all stay free, so the organizer can pick any
+// duration-long part of it. Nothing is returned if no one is free for long
+// enough.
+func findFreeWindows(window model.SearchWindow, duration time.Duration, participants []uuid.UUID, ranges []model.FreeRange) []model.FreeWindow {
+	segments := freeSegments(window, participants, ranges)
+
+	// the best window starting at each segment: who is free for all of
+	// the first duration from its start
+	type candidate struct {
+		first int
+		free  []int
+	}
+	var candidates []candidate
+	best := 0
+	for i := range segments {
+		free := segments[i].free
+		j := i
+		for len(free) > 0 && segments[j].end.Sub(segments[i].start) < duration && j+1 < len(segments) {
+			j++
+			free = intersectFree(free, segments[j].free)
+		}
+		if len(free) == 0 || segments[j].end.Sub(segments[i].start) < duration {
+			continue
+		}
+		if len(free) > best {
+			best, candidates = len(free), nil
+		}
+		if len(free) == best {
+			candidates = append(candidates, candidate{first: i, free: free})
+		}
+	}
+
+	windows := []model.FreeWindow{}
+	// a candidate starting inside an earlier window of the same people is
+	// part of that window
+	reached := make(map[string]time.Time)
+	for _, c := range candidates {
+		key := freeKey(c.free)
+		start := segments[c.first].start
+		if end, ok := reached[key]; ok && !start.After(end) {
+			continue
+		}
+
+		last := c.first
+		for last+1 < len(segments) && len(intersectFree(c.free, segments[last+1].free)) == len(c.free) {
+			last++
+		}
+		end := segments[last].end
+		reached[key] = end
+
+		ids := make([]uuid.UUID, len(c.free))
+		for k, i := range c.free {
+			ids[k] = participants[i]
+		}
+		windows = append(windows, model.FreeWindow{
+			StartTime:           start,
+			EndTime:             end,
+			FreeCount:           len(c.free),
+			TotalParticipants:   len(participants),
+			AvailabilityPercent: float64(len(c.free)) / float64(len(participants)) * 100,
+			ParticipantIDs:      ids,
+		})
+	}
+	return windows
+}
+
+// intersectFree intersects two ascending lists of participant indexes.
+func intersectFree(a, b []int) []int {
+	var out []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func freeKey(free []int) string {
+	key := make([]byte, 0, 4*len(free))
+	for _, i := range free {
+		key = append(key, byte(i>>24), byte(i>>16), byte(i>>8), byte(i))
+	}
+	return string(key)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// maxHeatmapBuckets caps how many buckets the slots of one event may be cut
+// into.
+const maxHeatmapBuckets = 10000
+
+// GetHeatmap builds the grid of answers for an event. A non-zero bucket
+// also cuts the time the slots cover into buckets of that length, aligned
+// to the clock, and counts who is free for each.
+func (s *availabilityService) GetHeatmap(ctx context.Context, eventID uuid.UUID, bucket time.Duration) (*model.Heatmap, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	slots := make([]model.TimeSlot, len(event.ProposedSlots))
+	copy(slots, event.ProposedSlots)
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].StartTime.Before(slots[j].StartTime) })
+
+	if bucket > 0 && len(slots) > 0 {
+		span := slotSpan(slots)
+		if n := span.end.Sub(span.start.Truncate(bucket)) / bucket; n > maxHeatmapBuckets {
+			return nil, apperr.BadRequest(fmt.Sprintf("bucket of %s cuts the slots into more than %d buckets", bucket, maxHeatmapBuckets))
+		}
+	}
+
+	availabilities, err := s.availRepo.GetByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	answers := make(map[answerKey]model.Availability, len(availabilities))
+	for _, a := range availabilities {
+		answers[answerKey{participantID: a.ParticipantID, slotID: a.SlotID}] = a
+	}
+
+	heatmap := &model.Heatmap{
+		EventID:      eventID,
+		Participants: make([]model.HeatmapParticipant, len(event.Participants)),
+		Slots:        make([]model.HeatmapSlot, len(slots)),
+	}
+	for j, p := range event.Participants {
+		heatmap.Participants[j] = model.HeatmapParticipant{ParticipantID: p.ID, Name: p.Name}
+	}
+
+	for i, slot := range slots {
+		row := model.HeatmapSlot{
+			SlotID:    slot.ID,
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+			Statuses:  make([]model.AvailabilityStatus, len(event.Participants)),
+		}
+		for j, p := range event.Participants {
+			a, ok := answers[answerKey{participantID: p.ID, slotID: slot.ID}]
+			if !ok {
+				row.Totals.NoResponse++
+				continue
+			}
+			row.Statuses[j] = a.Status
+			heatmap.Participants[j].Answered++
+			switch a.Status {
+			case model.AvailabilityStatusAvailable:
+				row.Totals.Available++
+			case model.AvailabilityStatusPartial:
+				row.Totals.Partial++
+			case model.AvailabilityStatusIfNeeded:
+				row.Totals.IfNeeded++
+			case model.AvailabilityStatusUnavailable:
+				row.Totals.Unavailable++
+			}
+		}
+		heatmap.Slots[i] = row
+	}
+
+	if len(slots) > 0 {
+		for j := range heatmap.Participants {
+			heatmap.Participants[j].Coverage = float64(heatmap.Participants[j].Answered) / float64(len(slots)) * 100
+		}
+	}
+
+	if bucket > 0 {
+		heatmap.BucketSize = bucket.String()
+		heatmap.Buckets = heatmapBuckets(slots, event.Participants, answers, bucket)
+	}
+	return heatmap, nil
+}
+
+// heatmapBuckets counts, for each bucket a slot overlaps, the participants
+// free for all of the bucket that the slots cover. A partial answer is free
+// only between its available_from and available_to.
+func heatmapBuckets(slots []model.TimeSlot, participants []model.Participant, answers map[answerKey]model.Availability, bucket time.Duration) []model.HeatmapBucket {
+	buckets := []model.HeatmapBucket{}
+	if len(slots) == 0 {
+		return buckets
+	}
+
+	// per participant, when they're free and when they'd come if needed
+	free := make([][]timeWindow, len(participants))
+	ifNeeded := make([][]timeWindow, len(participants))
+	for j, p := range participants {
+		var firm, all []timeWindow
+		for _, slot := range slots {
+			a, ok := answers[answerKey{participantID: p.ID, slotID: slot.ID}]
+			if !ok {
+				continue
+			}
+			w, ok := answerWindow(slot, a)
+			if !ok {
+				continue
+			}
+			if a.Status != model.AvailabilityStatusIfNeeded {
+				firm = append(firm, w)
+			}
+			all = append(all, w)
+		}
+		free[j] = mergeWindows(firm)
+		ifNeeded[j] = mergeWindows(all)
+	}
+
+	span := slotSpan(slots)
+	for start := span.start.Truncate(bucket); start.Before(span.end); start = start.Add(bucket) {
+		b := timeWindow{start: start, end: start.Add(bucket)}
+
+		// only the part of the bucket the slots cover has to be free
+		var covered *timeWindow
+		for _, slot := range slots {
+			if !b.overlaps(slot) {
+				continue
+			}
+			if covered == nil {
+				covered = &timeWindow{start: slot.StartTime, end: slot.EndTime}
+				continue
+			}
+			if slot.StartTime.Before(covered.start) {
+				covered.start = slot.StartTime
+			}
+			if slot.EndTime.After(covered.end) {
+				covered.end = slot.EndTime
+			}
+		}
+		if covered == nil {
+			continue
+		}
+		need := timeWindow{start: latest(b.start, covered.start), end: earliest(b.end, covered.end)}
+
+		hb := model.HeatmapBucket{StartTime: b.start, EndTime: b.end, Total: len(participants)}
+		for j := range participants {
+			switch {
+			case coversWindow(free[j], need):
+				hb.Free++
+			case coversWindow(ifNeeded[j], need):
+				hb.IfNeeded++
+			}
+		}
+		buckets = append(buckets, hb)
+	}
+	return buckets
+}
+
+// answerWindow is the part of slot an answer says the participant can make,
+// if any.
+func answerWindow(slot model.TimeSlot, a model.Availability) (timeWindow, bool) {
+	w := timeWindow{start: slot.StartTime, end: slot.EndTime}
+	switch a.Status {
+	case model.AvailabilityStatusAvailable, model.AvailabilityStatusIfNeeded:
+	case model.AvailabilityStatusPartial:
+		if a.AvailableFrom != nil {
+			w.start = latest(w.start, *a.AvailableFrom)
+		}
+		if a.AvailableTo != nil {
+			w.end = earliest(w.end, *a.AvailableTo)
+		}
+	default:
+		return timeWindow{}, false
+	}
+	return w, w.end.After(w.start)
+}
+
+// coversWindow reports whether one of windows, which must be merged, spans
+// all of w.
+func coversWindow(windows []timeWindow, w timeWindow) bool {
+	for _, fw := range windows {
+		if !fw.start.After(w.start) && !fw.end.Before(w.end) {
+			return true
+		}
+	}
+	return false
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
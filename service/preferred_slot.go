@@ -3,15 +3,15 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
 	"github.com/ram-ks/meeting-service/model"
 	"github.com/ram-ks/meeting-service/repository"
-)
-
-var (
-	ErrPreferredSlotNotFound = errors.New("preferred slot not found")
+	"github.com/ram-ks/meeting-service/validation"
 )
 
 type PreferredSlotService interface {
@@ -19,42 +19,68 @@ type PreferredSlotService interface {
 	GetByEmail(ctx context.Context, email string) ([]model.PreferredSlot, error)
 	Update(ctx context.Context, slotID uuid.UUID, req model.UpdatePreferredSlotRequest) (*model.PreferredSlot, error)
 	Delete(ctx context.Context, slotID uuid.UUID) error
+	// Bootstrap creates weekly recurring preferred slots from email's
+	// working hours. It refuses if email already has preferred slots.
+	Bootstrap(ctx context.Context, email string) ([]model.PreferredSlot, error)
+	// Import creates preferred slots from rows in one batch. In atomic mode
+	// any invalid row fails the whole import with a validation error; in
+	// best-effort mode invalid rows are reported and the rest imported.
+	Import(ctx context.Context, rows []model.PreferredSlotRow, mode model.ImportMode) (*model.PreferredSlotImportResult, error)
+	// Export returns emails' preferred slots as rows, and how many slots
+	// were left out because they don't fit the row form.
+	Export(ctx context.Context, emails []string) ([]model.PreferredSlotRow, int, error)
 }
 
 type preferredSlotService struct {
-	repo repository.PreferredSlotRepository
+	repo      repository.PreferredSlotRepository
+	hoursRepo repository.WorkingHoursRepository
+	audit     auditRecorder
+	now       func() time.Time
 }
 
-func NewPreferredSlotService(repo repository.PreferredSlotRepository) PreferredSlotService {
-	return &preferredSlotService{repo: repo}
+func NewPreferredSlotService(repo repository.PreferredSlotRepository, hoursRepo repository.WorkingHoursRepository, auditRepo repository.AuditRepository) PreferredSlotService {
+	return &preferredSlotService{
+		repo:      repo,
+		hoursRepo: hoursRepo,
+		audit:     auditRecorder{repo: auditRepo},
+		now:       time.Now,
+	}
 }
 
 func (s *preferredSlotService) Create(ctx context.Context, req model.CreatePreferredSlotRequest) (*model.PreferredSlot, error) {
-	startTime, err := parseTime(req.StartTime, req.Timezone)
-	if err != nil {
-		return nil, err
+	var v validation.Errors
+	times, ok := parseSlotTimes(&v, "", req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return nil, v.Err()
 	}
-	endTime, err := parseTime(req.EndTime, req.Timezone)
-	if err != nil {
-		return nil, err
+
+	if req.Level == "" {
+		req.Level = model.PreferenceIdeal
 	}
 
 	now := time.Now().UTC()
 	slot := &model.PreferredSlot{
 		ID:        uuid.New(),
 		Email:     req.Email,
-		StartTime: startTime,
-		EndTime:   endTime,
-		Timezone:  req.Timezone,
+		StartTime: times.Start,
+		EndTime:   times.End,
+		Timezone:  times.Timezone,
 		DayOfWeek: req.DayOfWeek,
+		RRule:     normalizeRRule(req.RRule),
+		ExDates:   normalizeExDates(req.ExDates),
+		Level:     req.Level,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if checkRecurrence(&v, slot); !v.Empty() {
+		return nil, v.Err()
+	}
 
 	if err := s.repo.Create(ctx, slot); err != nil {
 		return nil, err
 	}
 
+	s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityPreferredSlot, slot.ID, nil, nil, slot)
 	return slot, nil
 }
 
@@ -65,68 +91,203 @@ func (s *preferredSlotService) GetByEmail(ctx context.Context, email string) ([]
 func (s *preferredSlotService) Update(ctx context.Context, slotID uuid.UUID, req model.UpdatePreferredSlotRequest) (*model.PreferredSlot, error) {
 	slot, err := s.repo.GetByID(ctx, slotID)
 	if err != nil {
-		return nil, ErrPreferredSlotNotFound
+		return nil, notFound(err, ErrPreferredSlotNotFound)
 	}
 
-	timezone := slot.Timezone
-	if req.Timezone != nil {
-		timezone = *req.Timezone
-		slot.Timezone = timezone
-	}
-
-	if req.StartTime != nil {
-		startTime, err := parseTime(*req.StartTime, timezone)
-		if err != nil {
-			return nil, err
-		}
-		slot.StartTime = startTime
-	}
-
-	if req.EndTime != nil {
-		endTime, err := parseTime(*req.EndTime, timezone)
-		if err != nil {
-			return nil, err
-		}
-		slot.EndTime = endTime
+	before := *slot
+	var v validation.Errors
+	current := slotTimes{Start: slot.StartTime, End: slot.EndTime, Timezone: slot.Timezone}
+	times, ok := updateSlotTimes(&v, current, req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return nil, v.Err()
 	}
+	slot.StartTime, slot.EndTime, slot.Timezone = times.Start, times.End, times.Timezone
 
 	if req.DayOfWeek != nil {
 		slot.DayOfWeek = req.DayOfWeek
 	}
+	if req.RRule != nil {
+		slot.RRule = normalizeRRule(req.RRule)
+	}
+	if req.ExDates != nil {
+		slot.ExDates = normalizeExDates(*req.ExDates)
+	}
+	if req.Level != nil {
+		slot.Level = *req.Level
+	}
+	if checkRecurrence(&v, slot); !v.Empty() {
+		return nil, v.Err()
+	}
 
 	if err := s.repo.Update(ctx, slot); err != nil {
 		return nil, err
 	}
 
+	s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityPreferredSlot, slot.ID, nil, before, slot)
 	return slot, nil
 }
 
 func (s *preferredSlotService) Delete(ctx context.Context, slotID uuid.UUID) error {
-	_, err := s.repo.GetByID(ctx, slotID)
+	slot, err := s.repo.GetByID(ctx, slotID)
 	if err != nil {
-		return ErrPreferredSlotNotFound
+		return notFound(err, ErrPreferredSlotNotFound)
+	}
+
+	if err := s.repo.Delete(ctx, slotID); err != nil {
+		return err
 	}
-	return s.repo.Delete(ctx, slotID)
+
+	s.audit.record(ctx, model.AuditActionDelete, model.AuditEntityPreferredSlot, slot.ID, nil, slot, nil)
+	return nil
 }
 
-func parseTime(timeStr, timezone string) (time.Time, error) {
-	loc, err := time.LoadLocation(timezone)
+func (s *preferredSlotService) Bootstrap(ctx context.Context, email string) ([]model.PreferredSlot, error) {
+	profile, err := s.hoursRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, notFound(err, ErrWorkingHoursNotFound)
+	}
+	if len(profile.Hours) == 0 {
+		return nil, apperr.BadRequest("working hours profile has no hours to bootstrap from")
+	}
+
+	existing, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, ErrPreferredSlotsExist
+	}
+
+	loc, err := time.LoadLocation(profile.Timezone)
 	if err != nil {
-		return time.Time{}, err
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	slots := make([]model.PreferredSlot, 0, len(profile.Hours))
+	for _, group := range groupWorkingHours(profile.Hours) {
+		start, end, err := firstWorkingDay(group, now.In(loc))
+		if err != nil {
+			return nil, err
+		}
+		rule := "FREQ=WEEKLY;BYDAY=" + group.byDay()
+		slot := model.PreferredSlot{
+			ID:        uuid.New(),
+			Email:     email,
+			StartTime: start.UTC(),
+			EndTime:   end.UTC(),
+			Timezone:  profile.Timezone,
+			RRule:     &rule,
+			Level:     model.PreferenceIdeal,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.repo.Create(ctx, &slot); err != nil {
+			return nil, err
+		}
+		s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityPreferredSlot, slot.ID, nil, nil, slot)
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// workingHoursGroup is the days sharing one start and end time, which
+// become a single weekly rule.
+type workingHoursGroup struct {
+	start string
+	end   string
+	days  []time.Weekday
+}
+
+var rruleDays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (g workingHoursGroup) has(day time.Weekday) bool {
+	for _, d := range g.days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (g workingHoursGroup) byDay() string {
+	codes := make([]string, len(g.days))
+	for i, d := range g.days {
+		codes[i] = rruleDays[d]
 	}
+	return strings.Join(codes, ",")
+}
 
-	formats := []string{
-		"2006-01-02T15:04:05",
-		"2006-01-02T15:04:05Z07:00",
-		"2006-01-02 15:04:05",
-		time.RFC3339,
+// groupWorkingHours groups hours by their times, in order of first
+// appearance, with each group's days sorted from Sunday.
+func groupWorkingHours(hours []model.WorkingHours) []workingHoursGroup {
+	var groups []workingHoursGroup
+	index := make(map[[2]string]int)
+	for _, h := range hours {
+		key := [2]string{h.Start, h.End}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, workingHoursGroup{start: h.Start, end: h.End})
+		}
+		groups[i].days = append(groups[i].days, time.Weekday(h.DayOfWeek))
+	}
+	for i := range groups {
+		sort.Slice(groups[i].days, func(a, b int) bool { return groups[i].days[a] < groups[i].days[b] })
+	}
+	return groups
+}
+
+// firstWorkingDay finds the first of the group's days from today in the
+// profile's timezone, and the group's hours on it.
+func firstWorkingDay(group workingHoursGroup, today time.Time) (time.Time, time.Time, error) {
+	startMin, err := parseClock(group.start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endMin, err := parseClock(group.end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
 	}
 
-	for _, format := range formats {
-		if t, err := time.ParseInLocation(format, timeStr, loc); err == nil {
-			return t.UTC(), nil
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for i := 0; i < 7; i++ {
+		d := day.AddDate(0, 0, i)
+		if !group.has(d.Weekday()) {
+			continue
 		}
+		start := time.Date(d.Year(), d.Month(), d.Day(), startMin/60, startMin%60, 0, 0, d.Location())
+		end := time.Date(d.Year(), d.Month(), d.Day(), endMin/60, endMin%60, 0, 0, d.Location())
+		return start, end, nil
 	}
+	return time.Time{}, time.Time{}, errors.New("working hours group has no days")
+}
 
-	return time.Time{}, errors.New("invalid time format")
+// normalizeRRule trims rule and treats an empty one as no recurrence.
+func normalizeRRule(rule *string) *string {
+	if rule == nil {
+		return nil
+	}
+	trimmed := strings.TrimPrefix(strings.TrimSpace(*rule), "RRULE:")
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// normalizeExDates sorts the dates and drops repeats.
+func normalizeExDates(dates []string) []string {
+	if len(dates) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), dates...)
+	sort.Strings(sorted)
+	out := sorted[:1]
+	for _, d := range sorted[1:] {
+		if d != out[len(out)-1] {
+			out = append(out, d)
+		}
+	}
+	return out
 }
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/validation"
+	"github.com/teambition/rrule-go"
+)
+
+// MaxImportRows caps how many preferred slots one import may create.
+const MaxImportRows = 1000
+
+var everyDay = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+func (s *preferredSlotService) Import(ctx context.Context, rows []model.PreferredSlotRow, mode model.ImportMode) (*model.PreferredSlotImportResult, error) {
+	if len(rows) == 0 {
+		return nil, apperr.BadRequest("no rows to import")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, apperr.BadRequest("too many rows; import at most 1000 at a time")
+	}
+
+	now := s.now().UTC()
+	result := &model.PreferredSlotImportResult{
+		Mode:           mode,
+		Errors:         []model.ImportRowError{},
+		PreferredSlots: []model.PreferredSlot{},
+	}
+
+	// In atomic mode every problem is reported in one validation error,
+	// under rows[i]; in best-effort mode each row gets its own.
+	var all validation.Errors
+	for i, row := range rows {
+		if mode == model.ImportModeAtomic {
+			if slot, ok := slotFromRow(&all, validation.Index("rows", i)+".", row, now); ok {
+				result.PreferredSlots = append(result.PreferredSlots, slot)
+			}
+			continue
+		}
+
+		var v validation.Errors
+		slot, ok := slotFromRow(&v, "", row, now)
+		if !ok {
+			result.Errors = append(result.Errors, model.ImportRowError{Row: i, Errors: apperr.From(v.Err()).Fields})
+			continue
+		}
+		result.PreferredSlots = append(result.PreferredSlots, slot)
+	}
+	if !all.Empty() {
+		return nil, all.Err()
+	}
+
+	if err := s.repo.CreateBatch(ctx, result.PreferredSlots); err != nil {
+		return nil, err
+	}
+	for _, slot := range result.PreferredSlots {
+		s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityPreferredSlot, slot.ID, nil, nil, slot)
+	}
+
+	result.Imported = len(result.PreferredSlots)
+	result.Failed = len(result.Errors)
+	return result, nil
+}
+
+func (s *preferredSlotService) Export(ctx context.Context, emails []string) ([]model.PreferredSlotRow, int, error) {
+	slots, err := s.repo.GetByEmails(ctx, emails)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]model.PreferredSlotRow, 0, len(slots))
+	skipped := 0
+	for _, slot := range slots {
+		row, ok := rowFromSlot(slot)
+		if !ok {
+			skipped++
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, skipped, nil
+}
+
+// slotFromRow validates an import row, reporting against the fields under
+// prefix, and builds the daily or weekly recurring slot it describes.
+func slotFromRow(v *validation.Errors, prefix string, row model.PreferredSlotRow, now time.Time) (model.PreferredSlot, bool) {
+	email := strings.TrimSpace(row.Email)
+	_, emailErr := mail.ParseAddress(email)
+	if emailErr != nil {
+		v.Add(prefix+"email", "must be a valid email address")
+	}
+	days, daysErr := parseDays(row.Days)
+	if daysErr != nil {
+		v.Add(prefix+"days", "must be days such as MO,WE,FR")
+	}
+	start, startErr := parseClock(strings.TrimSpace(row.Start))
+	if startErr != nil {
+		v.Add(prefix+"start", "must be a time of day such as 09:30")
+	}
+	end, endErr := parseClock(strings.TrimSpace(row.End))
+	if endErr != nil {
+		v.Add(prefix+"end", "must be a time of day such as 09:30")
+	}
+	if startErr == nil && endErr == nil && end <= start {
+		v.Add(prefix+"end", "must be after start")
+	}
+	loc, locOK := v.Location(prefix+"timezone", strings.TrimSpace(row.Timezone))
+	level := model.PreferenceLevel(strings.ToLower(strings.TrimSpace(row.Level)))
+	if level == "" {
+		level = model.PreferenceIdeal
+	}
+	levelOK := level.IsValid()
+	if !levelOK {
+		v.Add(prefix+"level", "must be one of ideal, acceptable or avoid")
+	}
+
+	if emailErr != nil || daysErr != nil || startErr != nil || endErr != nil || end <= start || !locOK || !levelOK {
+		return model.PreferredSlot{}, false
+	}
+
+	rule := "FREQ=DAILY"
+	group := workingHoursGroup{start: strings.TrimSpace(row.Start), end: strings.TrimSpace(row.End), days: days}
+	if len(days) == 0 {
+		group.days = everyDay
+	} else {
+		rule = "FREQ=WEEKLY;BYDAY=" + group.byDay()
+	}
+	first, last, err := firstWorkingDay(group, now.In(loc))
+	if err != nil {
+		v.Add(prefix+"days", "%s", err.Error())
+		return model.PreferredSlot{}, false
+	}
+
+	return model.PreferredSlot{
+		ID:        uuid.New(),
+		Email:     email,
+		StartTime: first.UTC(),
+		EndTime:   last.UTC(),
+		Timezone:  strings.TrimSpace(row.Timezone),
+		RRule:     &rule,
+		Level:     level,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, true
+}
+
+// parseDays reads a list of RRULE day codes separated by commas, spaces or
+// semicolons, in any case, sorted from Sunday with repeats dropped.
+func parseDays(value string) ([]time.Weekday, error) {
+	var seen [7]bool
+	for _, code := range strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	}) {
+		day := -1
+		for i, c := range rruleDays {
+			if c == code {
+				day = i
+			}
+		}
+		if day < 0 {
+			return nil, fmt.Errorf("unknown day %q", code)
+		}
+		seen[day] = true
+	}
+
+	var days []time.Weekday
+	for i, ok := range seen {
+		if ok {
+			days = append(days, time.Weekday(i))
+		}
+	}
+	return days, nil
+}
+
+// rowFromSlot flattens a preferred slot for export. Only slots that fit the
+// row form are exported: one-off windows, with or without a day of week,
+// and rules that repeat every day or every week on given days, without
+// exceptions.
+func rowFromSlot(slot model.PreferredSlot) (model.PreferredSlotRow, bool) {
+	loc, err := time.LoadLocation(slot.Timezone)
+	if err != nil || len(slot.ExDates) > 0 {
+		return model.PreferredSlotRow{}, false
+	}
+	start, end := slot.StartTime.In(loc), slot.EndTime.In(loc)
+	if start.YearDay() != end.YearDay() || start.Year() != end.Year() {
+		return model.PreferredSlotRow{}, false
+	}
+
+	row := model.PreferredSlotRow{
+		Email:    slot.Email,
+		Start:    start.Format(validation.ClockLayout),
+		End:      end.Format(validation.ClockLayout),
+		Timezone: slot.Timezone,
+		Level:    string(slot.Level),
+	}
+
+	if !slot.IsRecurring() {
+		if slot.DayOfWeek != nil {
+			row.Days = rruleDays[*slot.DayOfWeek]
+		}
+		return row, true
+	}
+
+	opt, err := rrule.StrToROption(*slot.RRule)
+	if err != nil || opt.Interval > 1 || opt.Count > 0 || !opt.Until.IsZero() ||
+		len(opt.Bysetpos)+len(opt.Bymonth)+len(opt.Bymonthday)+len(opt.Byyearday)+len(opt.Byweekno)+len(opt.Byeaster) > 0 {
+		return model.PreferredSlotRow{}, false
+	}
+	switch opt.Freq {
+	case rrule.DAILY:
+		if len(opt.Byweekday) > 0 {
+			return model.PreferredSlotRow{}, false
+		}
+	case rrule.WEEKLY:
+		if len(opt.Byweekday) == 0 {
+			row.Days = rruleDays[start.Weekday()]
+			return row, true
+		}
+		codes := make([]string, len(opt.Byweekday))
+		for i, wd := range opt.Byweekday {
+			if wd.N() != 0 {
+				return model.PreferredSlotRow{}, false
+			}
+			codes[i] = wd.String()
+		}
+		row.Days = strings.Join(codes, ",")
+	default:
+		return model.PreferredSlotRow{}, false
+	}
+	return row, true
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPreferredSlotImportSuite(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC) // a Wednesday
+	newService := func(repo *MockPreferredSlotRepository) *preferredSlotService {
+		svc := NewPreferredSlotService(repo, nil, nil).(*preferredSlotService)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	rows := []model.PreferredSlotRow{
+		{Email: "alice@example.com", Days: "fr mo", Start: "09:00", End: "12:00", Timezone: "Europe/Berlin"},
+		{Email: "bob", Days: "MO,XX", Start: "13:00", End: "12:00", Timezone: "UTC", Level: "meh"},
+		{Email: "carol@example.com", Start: "14:00", End: "15:30", Timezone: "UTC", Level: "Acceptable"},
+	}
+
+	t.Run("Atomic_ReportsEveryRowAndImportsNothing", func(t *testing.T) {
+		mockRepo := new(MockPreferredSlotRepository)
+		svc := newService(mockRepo)
+
+		_, err := svc.Import(context.Background(), rows, model.ImportModeAtomic)
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "rows[1].email", Message: "must be a valid email address"},
+			{Field: "rows[1].days", Message: "must be days such as MO,WE,FR"},
+			{Field: "rows[1].end", Message: "must be after start"},
+			{Field: "rows[1].level", Message: "must be one of ideal, acceptable or avoid"},
+		}, apperr.From(err).Fields)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("BestEffort_ImportsValidRows", func(t *testing.T) {
+		mockRepo := new(MockPreferredSlotRepository)
+		svc := newService(mockRepo)
+
+		mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(slots []model.PreferredSlot) bool {
+			return len(slots) == 2
+		})).Return(nil)
+
+		result, err := svc.Import(context.Background(), rows, model.ImportModeBestEffort)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, 1, result.Errors[0].Row)
+		assert.Len(t, result.Errors[0].Errors, 4)
+
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		alice := result.PreferredSlots[0]
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR", *alice.RRule)
+		assert.Equal(t, time.Date(2026, 3, 6, 9, 0, 0, 0, berlin).UTC(), alice.StartTime)
+		assert.Equal(t, model.PreferenceIdeal, alice.Level)
+
+		carol := result.PreferredSlots[1]
+		assert.Equal(t, "FREQ=DAILY", *carol.RRule)
+		assert.Equal(t, time.Date(2026, 3, 4, 14, 0, 0, 0, time.UTC), carol.StartTime)
+		assert.Equal(t, model.PreferenceAcceptable, carol.Level)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Export_RoundTripsImportedRows", func(t *testing.T) {
+		mockRepo := new(MockPreferredSlotRepository)
+		svc := newService(mockRepo)
+
+		var stored []model.PreferredSlot
+		for _, row := range []model.PreferredSlotRow{rows[0], rows[2]} {
+			var v validation.Errors
+			slot, ok := slotFromRow(&v, "", row, now)
+			assert.True(t, ok)
+			stored = append(stored, slot)
+		}
+		weird := "FREQ=MONTHLY;BYMONTHDAY=1"
+		stored = append(stored, model.PreferredSlot{Email: "dave@example.com", StartTime: now, EndTime: now.Add(time.Hour), Timezone: "UTC", RRule: &weird})
+
+		emails := []string{"alice@example.com", "carol@example.com", "dave@example.com"}
+		mockRepo.On("GetByEmails", mock.Anything, emails).Return(stored, nil)
+
+		exported, skipped, err := svc.Export(context.Background(), emails)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, skipped)
+		assert.Equal(t, []model.PreferredSlotRow{
+			{Email: "alice@example.com", Days: "MO,FR", Start: "09:00", End: "12:00", Timezone: "Europe/Berlin", Level: "ideal"},
+			{Email: "carol@example.com", Start: "14:00", End: "15:30", Timezone: "UTC", Level: "acceptable"},
+		}, exported)
+	})
+}
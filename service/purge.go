@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// EventPurger periodically hard deletes soft-deleted events whose retention
+// period has passed.
+type EventPurger struct {
+	eventService EventService
+	interval     time.Duration
+}
+
+func NewEventPurger(eventService EventService, interval time.Duration) *EventPurger {
+	return &EventPurger{eventService: eventService, interval: interval}
+}
+
+// Run purges once immediately and then on every tick until ctx is cancelled.
+func (p *EventPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.eventService.PurgeDeletedEvents(WithActor(ctx, systemActor)); err != nil {
+			slog.ErrorContext(ctx, "event purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
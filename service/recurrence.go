@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/validation"
+	"github.com/teambition/rrule-go"
+)
+
+// parseRecurrence reads an RRULE for a window whose first occurrence starts
+// at start in loc. The window itself sets the time of day, so rules that
+// repeat more than daily or pick their own hours are rejected, as is a
+// DTSTART, which start already gives.
+func parseRecurrence(rule string, start time.Time, loc *time.Location) (*rrule.RRule, error) {
+	opt, err := rrule.StrToROptionInLocation(strings.TrimSpace(rule), loc)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !opt.Dtstart.IsZero():
+		return nil, errors.New("must not set DTSTART; start_time is the first occurrence")
+	case opt.Freq > rrule.DAILY:
+		return nil, errors.New("must repeat at most daily")
+	case len(opt.Byhour) > 0 || len(opt.Byminute) > 0 || len(opt.Bysecond) > 0:
+		return nil, errors.New("must not set BYHOUR, BYMINUTE or BYSECOND; start_time and end_time give the time of day")
+	}
+	opt.Dtstart = start.In(loc)
+	return rrule.NewRRule(*opt)
+}
+
+// checkRecurrence validates a preferred slot's rrule and exdates against its
+// times, reporting against the rrule field.
+func checkRecurrence(v *validation.Errors, slot *model.PreferredSlot) {
+	if !slot.IsRecurring() {
+		return
+	}
+	if slot.DayOfWeek != nil {
+		v.Add("day_of_week", "must not be set with rrule; use BYDAY")
+		return
+	}
+	loc, err := time.LoadLocation(slot.Timezone)
+	if err != nil {
+		return
+	}
+	if _, err := parseRecurrence(*slot.RRule, slot.StartTime, loc); err != nil {
+		v.Add("rrule", "is not a valid recurrence rule: %s", err.Error())
+	}
+}
+
+type timeWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+func (w timeWindow) contains(slot model.TimeSlot) bool {
+	return !slot.StartTime.Before(w.start) && !slot.EndTime.After(w.end)
+}
+
+func (w timeWindow) overlaps(slot model.TimeSlot) bool {
+	return slot.StartTime.Before(w.end) && slot.EndTime.After(w.start)
+}
+
+// slotSpan is the earliest start and latest end across slots, which must
+// not be empty.
+func slotSpan(slots []model.TimeSlot) timeWindow {
+	span := timeWindow{start: slots[0].StartTime, end: slots[0].EndTime}
+	for _, slot := range slots[1:] {
+		if slot.StartTime.Before(span.start) {
+			span.start = slot.StartTime
+		}
+		if slot.EndTime.After(span.end) {
+			span.end = slot.EndTime
+		}
+	}
+	return span
+}
+
+// occurrences expands a recurring preferred slot into the windows that
+// overlap from-to.
+func occurrences(pref model.PreferredSlot, from, to time.Time) ([]timeWindow, error) {
+	loc, err := time.LoadLocation(pref.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := parseRecurrence(*pref.RRule, pref.StartTime, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(pref.ExDates))
+	for _, d := range pref.ExDates {
+		skip[d] = true
+	}
+
+	length := pref.EndTime.Sub(pref.StartTime)
+	var windows []timeWindow
+	// an occurrence starting up to one window length before from still
+	// overlaps it
+	for _, start := range rule.Between(from.Add(-length), to, true) {
+		if skip[start.In(loc).Format(validation.DateLayout)] {
+			continue
+		}
+		windows = append(windows, timeWindow{start: start, end: start.Add(length)})
+	}
+	return windows, nil
+}
+
+// preferenceIndex answers whether a slot falls in a preferred window.
+// Recurring preferences are expanded once, over the span of the slots being
+// scored, rather than per slot.
+type preferenceIndex struct {
+	windows map[uuid.UUID][]timeWindow
+}
+
+func newPreferenceIndex(ctx context.Context, prefs []model.PreferredSlot, slots []model.TimeSlot) preferenceIndex {
+	idx := preferenceIndex{windows: make(map[uuid.UUID][]timeWindow)}
+	if len(slots) == 0 {
+		return idx
+	}
+
+	span := slotSpan(slots)
+	for _, pref := range prefs {
+		if !pref.IsRecurring() {
+			continue
+		}
+		windows, err := occurrences(pref, span.start, span.end)
+		if err != nil {
+			// rules are validated on write, so this is stored data gone bad
+			slog.WarnContext(ctx, "skipping unreadable recurring preference", "preferred_slot_id", pref.ID, "error", err)
+			continue
+		}
+		idx.windows[pref.ID] = windows
+	}
+	return idx
+}
+
+func (idx preferenceIndex) matches(slot model.TimeSlot, pref model.PreferredSlot) bool {
+	if !pref.IsRecurring() {
+		return slotOverlapsPreference(slot, pref)
+	}
+	for _, w := range idx.windows[pref.ID] {
+		if w.contains(slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// level is the participant's preference for slot given their preferred
+// slots: avoid if any avoid window matches, since one objection outweighs
+// any number of preferences, otherwise the highest-weighted level that
+// matches, or empty if none do.
+func (idx preferenceIndex) level(slot model.TimeSlot, prefs []model.PreferredSlot) model.PreferenceLevel {
+	var best model.PreferenceLevel
+	for _, pref := range prefs {
+		if !idx.matches(slot, pref) {
+			continue
+		}
+		level := pref.Level
+		if level == "" {
+			level = model.PreferenceIdeal
+		}
+		if level == model.PreferenceAvoid {
+			return level
+		}
+		if best == "" || level.Weight() > best.Weight() {
+			best = level
+		}
+	}
+	return best
+}
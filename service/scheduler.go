@@ -2,37 +2,76 @@ package service
 
 import (
 	"context"
+	"log/slog"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ram-ks/meeting-service/metrics"
 	"github.com/ram-ks/meeting-service/model"
 	"github.com/ram-ks/meeting-service/repository"
 )
 
+// recentChangeWindow is how far back an answer change is flagged in the
+// explain output.
+const recentChangeWindow = 48 * time.Hour
+
+// ifNeededWeight is how much of an attendee an if-needed answer is worth
+// taken off the availability score.
+const ifNeededWeight = 0.5
+
 type SchedulerService interface {
 	GetRecommendations(ctx context.Context, eventID uuid.UUID) (*model.RecommendationResponse, error)
+	ExplainRecommendations(ctx context.Context, eventID uuid.UUID) (*model.RecommendationResponse, error)
 }
 
 type schedulerService struct {
 	eventRepo         repository.EventRepository
 	availRepo         repository.AvailabilityRepository
 	preferredSlotRepo repository.PreferredSlotRepository
+	blackoutRepo      repository.BlackoutRepository
+	workingHoursRepo  repository.WorkingHoursRepository
+	now               func() time.Time
 }
 
-func NewSchedulerService(eventRepo repository.EventRepository, availRepo repository.AvailabilityRepository, preferredSlotRepo repository.PreferredSlotRepository) SchedulerService {
+func NewSchedulerService(eventRepo repository.EventRepository, availRepo repository.AvailabilityRepository, preferredSlotRepo repository.PreferredSlotRepository, blackoutRepo repository.BlackoutRepository, workingHoursRepo repository.WorkingHoursRepository) SchedulerService {
 	return &schedulerService{
 		eventRepo:         eventRepo,
 		availRepo:         availRepo,
 		preferredSlotRepo: preferredSlotRepo,
+		blackoutRepo:      blackoutRepo,
+		workingHoursRepo:  workingHoursRepo,
+		now:               time.Now,
 	}
 }
 
-func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.UUID) (*model.RecommendationResponse, error) {
+func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.UUID) (recs *model.RecommendationResponse, err error) {
+	ctx, span := tracer.Start(ctx, "SchedulerService.GetRecommendations", eventIDAttr(eventID))
+	defer func() { endSpan(span, err) }()
+
+	return s.recommend(ctx, eventID, false)
+}
+
+// ExplainRecommendations is GetRecommendations with a per-participant
+// breakdown attached to every slot.
+func (s *schedulerService) ExplainRecommendations(ctx context.Context, eventID uuid.UUID) (recs *model.RecommendationResponse, err error) {
+	ctx, span := tracer.Start(ctx, "SchedulerService.ExplainRecommendations", eventIDAttr(eventID))
+	defer func() { endSpan(span, err) }()
+
+	return s.recommend(ctx, eventID, true)
+}
+
+func (s *schedulerService) recommend(ctx context.Context, eventID uuid.UUID, explain bool) (*model.RecommendationResponse, error) {
+	timer := prometheus.NewTimer(metrics.RecommendationDuration)
+	defer timer.ObserveDuration()
+
 	event, err := s.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
-		return nil, ErrEventNotFound
+		return nil, notFound(err, ErrEventNotFound)
 	}
+	metrics.RecommendationSlots.Observe(float64(len(event.ProposedSlots)))
 
 	availabilities, err := s.availRepo.GetByEventID(ctx, eventID)
 	if err != nil {
@@ -45,6 +84,7 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 	}
 
 	prefByEmail := make(map[string][]model.PreferredSlot)
+	var prefIndex preferenceIndex
 	if len(emails) > 0 {
 		preferredSlots, err := s.preferredSlotRepo.GetByEmails(ctx, emails)
 		if err == nil {
@@ -52,49 +92,151 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 				key := strings.ToLower(ps.Email)
 				prefByEmail[key] = append(prefByEmail[key], ps)
 			}
+			prefIndex = newPreferenceIndex(ctx, preferredSlots, event.ProposedSlots)
 		}
 	}
 
+	var blackouts blackoutIndex
+	if len(event.ProposedSlots) > 0 {
+		blackouts, err = blackoutsByEmail(ctx, s.blackoutRepo, emails, slotSpan(event.ProposedSlots))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	profiles, err := hoursProfilesByEmail(ctx, s.workingHoursRepo, emails)
+	if err != nil {
+		return nil, err
+	}
+
 	availBySlot := make(map[uuid.UUID][]model.Availability)
 	for _, a := range availabilities {
 		availBySlot[a.SlotID] = append(availBySlot[a.SlotID], a)
 	}
 
+	var changes map[answerKey]answerChange
+	if explain {
+		revisions, err := s.availRepo.GetRevisionsByEventID(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		changes = recentAnswerChanges(revisions, s.now().Add(-recentChangeWindow))
+	}
+
 	totalParticipants := len(event.Participants)
-	var recommendations []model.Recommendation
+	var recommendations, rejectedSlots []model.Recommendation
 
 	for _, slot := range event.ProposedSlots {
 		slotAvailabilities := availBySlot[slot.ID]
 		availableCount := 0
+		ifNeededCount := 0
 		preferredCount := 0
+		avoidCount := 0
+		preferenceSum := 0.0
 
-		respondedParticipants := make(map[uuid.UUID]bool)
+		var conflicts []model.BlackoutConflict
+		blockedBy := make(map[uuid.UUID]model.BlackoutConflict)
+		for _, p := range event.Participants {
+			if b, ok := blackouts.covering(slot, p.Email); ok {
+				conflict := model.BlackoutConflict{ParticipantID: p.ID, Name: p.Name, BlackoutID: b.ID, Reason: b.Reason}
+				conflicts = append(conflicts, conflict)
+				blockedBy[p.ID] = conflict
+			}
+		}
+
+		answers := make(map[uuid.UUID]model.Availability)
 		for _, a := range slotAvailabilities {
-			respondedParticipants[a.ParticipantID] = true
+			answers[a.ParticipantID] = a
+			if _, blocked := blockedBy[a.ParticipantID]; blocked {
+				continue
+			}
 			if a.Status == model.AvailabilityStatusAvailable {
 				availableCount++
 			} else if a.Status == model.AvailabilityStatusPartial {
 				availableCount++
+			} else if a.Status == model.AvailabilityStatusIfNeeded {
+				availableCount++
+				ifNeededCount++
 			}
 		}
 
+		var hoursConflicts []model.HoursConflict
+		outsideHours := make(map[uuid.UUID]model.HoursViolation)
+		outsideHoursCount := 0
+		rejected := false
 		for _, p := range event.Participants {
-			prefs := prefByEmail[strings.ToLower(p.Email)]
-			for _, pref := range prefs {
-				if slotOverlapsPreference(slot, pref) {
-					preferredCount++
-					break
+			profile, ok := profiles[strings.ToLower(p.Email)]
+			if !ok {
+				continue
+			}
+			violation := profile.check(slot)
+			switch violation {
+			case "":
+				continue
+			case model.HoursViolationHardLimits:
+				rejected = true
+			case model.HoursViolationWorkingHours:
+				outsideHoursCount++
+			}
+			outsideHours[p.ID] = violation
+			hoursConflicts = append(hoursConflicts, profile.conflict(slot, p, violation))
+		}
+
+		var explanations []model.ParticipantExplanation
+		for _, p := range event.Participants {
+			level := prefIndex.level(slot, prefByEmail[strings.ToLower(p.Email)])
+			preferred := level != "" && level.Weight() > 0
+			switch {
+			case level == model.PreferenceAvoid:
+				avoidCount++
+			case preferred:
+				preferredCount++
+			}
+			if level != "" {
+				preferenceSum += level.Weight()
+			}
+
+			if explain {
+				exp := model.ParticipantExplanation{
+					ParticipantID: p.ID,
+					Name:          p.Name,
+					Preferred:     preferred,
+					Preference:    level,
+				}
+				if a, ok := answers[p.ID]; ok {
+					exp.Responded = true
+					exp.Status = a.Status
+					exp.Note = a.Note
+					exp.SubmissionNote = p.SubmissionNote
 				}
+				if conflict, ok := blockedBy[p.ID]; ok {
+					exp.BlackedOut = true
+					exp.BlackoutReason = conflict.Reason
+				}
+				exp.OutsideHours = outsideHours[p.ID]
+				if change, ok := changes[answerKey{participantID: p.ID, slotID: slot.ID}]; ok {
+					exp.RecentlyChanged = true
+					exp.PreviousStatus = change.previousStatus
+					changedAt := change.changedAt
+					exp.ChangedAt = &changedAt
+				}
+				explanations = append(explanations, exp)
 			}
 		}
 
+		// if-needed answers count towards attendance but only half towards
+		// the score, so a slot people would rather avoid ranks lower
 		percent := 0.0
 		if totalParticipants > 0 {
-			percent = float64(availableCount) / float64(totalParticipants) * 100
+			percent = (float64(availableCount) - ifNeededWeight*float64(ifNeededCount)) / float64(totalParticipants) * 100
 		}
 
+		// avoid windows count against the score, so it runs from -100,
+		// everyone avoiding the slot, to 100, everyone finding it ideal
+		preferenceScore := 0.0
 		preferredPercent := 0.0
 		if totalParticipants > 0 {
+			preferenceScore = preferenceSum / float64(totalParticipants) * 100
 			preferredPercent = float64(preferredCount) / float64(totalParticipants) * 100
 		}
 
@@ -103,7 +245,9 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 		if totalParticipants > 0 {
 			isPerfect =
 				availableCount == totalParticipants &&
-					preferredCount == totalParticipants
+					preferredCount == totalParticipants &&
+					ifNeededCount == 0 &&
+					len(hoursConflicts) == 0
 		}
 
 		rec := model.Recommendation{
@@ -112,9 +256,20 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 			AvailableCount:      availableCount,
 			TotalParticipants:   totalParticipants,
 			AvailabilityPercent: percent,
+			IfNeededCount:       ifNeededCount,
 			PreferredCount:      preferredCount,
 			PreferredPercent:    preferredPercent,
+			AvoidCount:          avoidCount,
+			PreferenceScore:     preferenceScore,
 			IsPerfectMatch:      isPerfect,
+			Blackouts:           conflicts,
+			OutsideHoursCount:   outsideHoursCount,
+			HoursConflicts:      hoursConflicts,
+			Explain:             explanations,
+		}
+		if rejected {
+			rejectedSlots = append(rejectedSlots, rec)
+			continue
 		}
 		recommendations = append(recommendations, rec)
 	}
@@ -126,15 +281,20 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 		if recommendations[i].AvailabilityPercent != recommendations[j].AvailabilityPercent {
 			return recommendations[i].AvailabilityPercent > recommendations[j].AvailabilityPercent
 		}
-		return recommendations[i].PreferredPercent > recommendations[j].PreferredPercent
+		if recommendations[i].OutsideHoursCount != recommendations[j].OutsideHoursCount {
+			return recommendations[i].OutsideHoursCount < recommendations[j].OutsideHoursCount
+		}
+		return recommendations[i].PreferenceScore > recommendations[j].PreferenceScore
 	})
 
 	response := &model.RecommendationResponse{
 		EventID:      eventID,
 		PerfectSlots: []model.Recommendation{},
 		BestMatches:  []model.Recommendation{},
+		Rejected:     []model.Recommendation{},
 	}
 
+	response.Rejected = append(response.Rejected, rejectedSlots...)
 	for _, rec := range recommendations {
 		if rec.IsPerfectMatch {
 			response.PerfectSlots = append(response.PerfectSlots, rec)
@@ -146,6 +306,74 @@ func (s *schedulerService) GetRecommendations(ctx context.Context, eventID uuid.
 	return response, nil
 }
 
+// blackoutIndex holds participants' blackouts by lowercased email, with the
+// span each covers.
+type blackoutIndex map[string][]blackoutSpan
+
+type blackoutSpan struct {
+	blackout model.Blackout
+	window   timeWindow
+}
+
+// blackoutsByEmail loads the blackouts of emails that could touch span.
+// Unlike preferences, a failed lookup fails the request: recommending a slot
+// someone is away for is worse than no answer.
+func blackoutsByEmail(ctx context.Context, repo repository.BlackoutRepository, emails []string, span timeWindow) (blackoutIndex, error) {
+	idx := make(blackoutIndex)
+	if len(emails) == 0 {
+		return idx, nil
+	}
+
+	blackouts, err := repo.GetByEmails(ctx, emails, span.start, span.end)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blackouts {
+		window, err := blackoutWindow(b)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping unreadable blackout", "blackout_id", b.ID, "error", err)
+			continue
+		}
+		key := strings.ToLower(b.Email)
+		idx[key] = append(idx[key], blackoutSpan{blackout: b, window: window})
+	}
+	return idx, nil
+}
+
+// covering returns the first of email's blackouts that slot overlaps.
+func (idx blackoutIndex) covering(slot model.TimeSlot, email string) (model.Blackout, bool) {
+	for _, b := range idx[strings.ToLower(email)] {
+		if b.window.overlaps(slot) {
+			return b.blackout, true
+		}
+	}
+	return model.Blackout{}, false
+}
+
+// hoursProfilesByEmail loads participants' working-hours profiles by
+// lowercased email. Like blackouts, a failed lookup fails the request, since
+// hard limits decide which slots may be offered at all.
+func hoursProfilesByEmail(ctx context.Context, repo repository.WorkingHoursRepository, emails []string) (map[string]hoursProfile, error) {
+	profiles := make(map[string]hoursProfile)
+	if len(emails) == 0 {
+		return profiles, nil
+	}
+
+	stored, err := repo.GetByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range stored {
+		profile, err := newHoursProfile(p)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping unreadable working hours profile", "working_hours_id", p.ID, "error", err)
+			continue
+		}
+		profiles[strings.ToLower(p.Email)] = profile
+	}
+	return profiles, nil
+}
+
 func slotOverlapsPreference(slot model.TimeSlot, pref model.PreferredSlot) bool {
 	if pref.DayOfWeek != nil {
 		slotDay := int(slot.StartTime.Weekday())
@@ -161,3 +389,41 @@ func slotOverlapsPreference(slot model.TimeSlot, pref model.PreferredSlot) bool
 
 	return slotStart >= prefStart && slotEnd <= prefEnd
 }
+
+type answerKey struct {
+	participantID uuid.UUID
+	slotID        uuid.UUID
+}
+
+type answerChange struct {
+	previousStatus model.AvailabilityStatus
+	changedAt      time.Time
+}
+
+// recentAnswerChanges finds answers whose latest revision replaced an earlier
+// one after since. Deleted answers have nothing left to flag.
+func recentAnswerChanges(revisions []model.AvailabilityRevision, since time.Time) map[answerKey]answerChange {
+	latest := make(map[answerKey]model.AvailabilityRevision)
+	previous := make(map[answerKey]model.AvailabilityRevision)
+	for _, rev := range revisions {
+		key := answerKey{participantID: rev.ParticipantID, slotID: rev.SlotID}
+		if cur, ok := latest[key]; !ok || rev.Revision > cur.Revision {
+			if ok {
+				previous[key] = cur
+			}
+			latest[key] = rev
+		} else if prev, ok := previous[key]; !ok || rev.Revision > prev.Revision {
+			previous[key] = rev
+		}
+	}
+
+	changes := make(map[answerKey]answerChange)
+	for key, rev := range latest {
+		prev, ok := previous[key]
+		if !ok || rev.Deleted || rev.CreatedAt.Before(since) {
+			continue
+		}
+		changes[key] = answerChange{previousStatus: prev.Status, changedAt: rev.CreatedAt}
+	}
+	return changes
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
 	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -29,12 +32,12 @@ func (m *MockEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return args.Get(0).(*model.Event), args.Error(1)
 }
 
-func (m *MockEventRepository) List(ctx context.Context, organizerID uuid.UUID) ([]model.Event, error) {
-	args := m.Called(ctx, organizerID)
+func (m *MockEventRepository) List(ctx context.Context, filter model.EventFilter) (*model.EventPage, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Event), args.Error(1)
+	return args.Get(0).(*model.EventPage), args.Error(1)
 }
 
 func (m *MockEventRepository) Update(ctx context.Context, event *model.Event) error {
@@ -47,6 +50,53 @@ func (m *MockEventRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockEventRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*model.Event, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Event), args.Error(1)
+}
+
+func (m *MockEventRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEventRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockEventRepository) ListPastDeadline(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockEventRepository) ListDueReminders(ctx context.Context, now, until time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, now, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockEventRepository) CloseResponses(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEventRepository) MarkReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockEventRepository) CreateSlot(ctx context.Context, slot *model.TimeSlot) error {
 	args := m.Called(ctx, slot)
 	return args.Error(0)
@@ -113,9 +163,12 @@ func (m *MockAvailabilityRepository) Create(ctx context.Context, availability *m
 	return args.Error(0)
 }
 
-func (m *MockAvailabilityRepository) Upsert(ctx context.Context, availability *model.Availability) error {
-	args := m.Called(ctx, availability)
-	return args.Error(0)
+func (m *MockAvailabilityRepository) SaveAnswers(ctx context.Context, participantID uuid.UUID, submissionNote string, answers []*model.Availability) ([]model.Availability, error) {
+	args := m.Called(ctx, participantID, submissionNote, answers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Availability), args.Error(1)
 }
 
 func (m *MockAvailabilityRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]model.Availability, error) {
@@ -134,16 +187,24 @@ func (m *MockAvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID)
 	return args.Get(0).(*model.Availability), args.Error(1)
 }
 
-func (m *MockAvailabilityRepository) Update(ctx context.Context, availability *model.Availability) error {
-	args := m.Called(ctx, availability)
+func (m *MockAvailabilityRepository) Update(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error {
+	args := m.Called(ctx, availability, revision)
 	return args.Error(0)
 }
 
-func (m *MockAvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockAvailabilityRepository) Delete(ctx context.Context, availability *model.Availability, revision *model.AvailabilityRevision) error {
+	args := m.Called(ctx, availability, revision)
 	return args.Error(0)
 }
 
+func (m *MockAvailabilityRepository) GetRevisionsByEventID(ctx context.Context, eventID uuid.UUID) ([]model.AvailabilityRevision, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AvailabilityRevision), args.Error(1)
+}
+
 type MockPreferredSlotRepository struct {
 	mock.Mock
 }
@@ -153,6 +214,11 @@ func (m *MockPreferredSlotRepository) Create(ctx context.Context, slot *model.Pr
 	return args.Error(0)
 }
 
+func (m *MockPreferredSlotRepository) CreateBatch(ctx context.Context, slots []model.PreferredSlot) error {
+	args := m.Called(ctx, slots)
+	return args.Error(0)
+}
+
 func (m *MockPreferredSlotRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PreferredSlot, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -187,13 +253,102 @@ func (m *MockPreferredSlotRepository) Delete(ctx context.Context, id uuid.UUID)
 	return args.Error(0)
 }
 
+type MockBlackoutRepository struct {
+	mock.Mock
+}
+
+// noBlackouts is a blackout repository for tests that don't exercise
+// blackouts.
+func noBlackouts() *MockBlackoutRepository {
+	m := new(MockBlackoutRepository)
+	m.On("GetByEmails", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Blackout{}, nil).Maybe()
+	return m
+}
+
+func (m *MockBlackoutRepository) Create(ctx context.Context, blackout *model.Blackout) error {
+	args := m.Called(ctx, blackout)
+	return args.Error(0)
+}
+
+func (m *MockBlackoutRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Blackout, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Blackout), args.Error(1)
+}
+
+func (m *MockBlackoutRepository) GetByEmail(ctx context.Context, email string) ([]model.Blackout, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Blackout), args.Error(1)
+}
+
+func (m *MockBlackoutRepository) GetByEmails(ctx context.Context, emails []string, from, to time.Time) ([]model.Blackout, error) {
+	args := m.Called(ctx, emails, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Blackout), args.Error(1)
+}
+
+func (m *MockBlackoutRepository) Update(ctx context.Context, blackout *model.Blackout) error {
+	args := m.Called(ctx, blackout)
+	return args.Error(0)
+}
+
+func (m *MockBlackoutRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockWorkingHoursRepository struct {
+	mock.Mock
+}
+
+// noWorkingHours is a working-hours repository for tests that don't
+// exercise profiles.
+func noWorkingHours() *MockWorkingHoursRepository {
+	m := new(MockWorkingHoursRepository)
+	m.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.WorkingHoursProfile{}, nil).Maybe()
+	return m
+}
+
+func (m *MockWorkingHoursRepository) Upsert(ctx context.Context, profile *model.WorkingHoursProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockWorkingHoursRepository) GetByEmail(ctx context.Context, email string) (*model.WorkingHoursProfile, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WorkingHoursProfile), args.Error(1)
+}
+
+func (m *MockWorkingHoursRepository) GetByEmails(ctx context.Context, emails []string) ([]model.WorkingHoursProfile, error) {
+	args := m.Called(ctx, emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.WorkingHoursProfile), args.Error(1)
+}
+
+func (m *MockWorkingHoursRepository) Delete(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
 func TestSchedulerServiceSuite(t *testing.T) {
 	t.Run("GetRecommendations_NoAvailability_AllPreferred_IsBestMatch", func(t *testing.T) {
 		mockEventRepo := new(MockEventRepository)
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -241,6 +396,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		assert.Equal(t, float64(0), rec.AvailabilityPercent)
 		assert.Equal(t, 2, rec.PreferredCount)
 		assert.Equal(t, float64(100), rec.PreferredPercent)
+		assert.Equal(t, float64(100), rec.PreferenceScore)
 		assert.False(t, rec.IsPerfectMatch)
 
 		mockEventRepo.AssertExpectations(t)
@@ -253,7 +409,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -299,7 +455,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		assert.Equal(t, 2, result.PerfectSlots[0].TotalParticipants)
 		assert.Equal(t, float64(100), result.PerfectSlots[0].AvailabilityPercent)
 		assert.Equal(t, 2, result.PerfectSlots[0].PreferredCount)
-		assert.Equal(t, float64(100), result.PerfectSlots[0].PreferredPercent)
+		assert.Equal(t, float64(100), result.PerfectSlots[0].PreferenceScore)
 		assert.True(t, result.PerfectSlots[0].IsPerfectMatch)
 
 		mockEventRepo.AssertExpectations(t)
@@ -312,7 +468,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -362,24 +518,43 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
-		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(nil, errors.New("not found"))
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(nil, apperr.ErrNotFound.Wrap(sql.ErrNoRows))
 
 		result, err := svc.GetRecommendations(context.Background(), eventID)
 
 		assert.Nil(t, result)
-		assert.Equal(t, ErrEventNotFound, err)
+		assert.ErrorIs(t, err, ErrEventNotFound)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
 		mockEventRepo.AssertExpectations(t)
 	})
 
+	t.Run("GetRecommendations_EventLookupFails", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		dbErr := errors.New("connection refused")
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(nil, dbErr)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, dbErr)
+		assert.NotErrorIs(t, err, ErrEventNotFound)
+	})
+
 	t.Run("GetRecommendations_NoParticipants_NoSlots", func(t *testing.T) {
 		mockEventRepo := new(MockEventRepository)
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		event := &model.Event{
@@ -408,7 +583,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -452,7 +627,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -503,7 +678,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -557,7 +732,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -594,7 +769,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, result.BestMatches, 1)
 		assert.Equal(t, 2, result.BestMatches[0].PreferredCount)
-		assert.Equal(t, float64(100), result.BestMatches[0].PreferredPercent)
+		assert.Equal(t, float64(100), result.BestMatches[0].PreferenceScore)
 
 		mockEventRepo.AssertExpectations(t)
 		mockAvailRepo.AssertExpectations(t)
@@ -606,7 +781,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -640,6 +815,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 
 		assert.Equal(t, 0, result.BestMatches[0].PreferredCount)
 		assert.Equal(t, float64(0), result.BestMatches[0].PreferredPercent)
+		assert.Equal(t, float64(0), result.BestMatches[0].PreferenceScore)
 		assert.False(t, result.BestMatches[0].IsPerfectMatch)
 
 		mockEventRepo.AssertExpectations(t)
@@ -652,7 +828,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -697,7 +873,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID1 := uuid.New()
@@ -756,7 +932,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID1 := uuid.New()
@@ -797,19 +973,187 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		assert.Len(t, result.BestMatches, 2)
 
 		assert.Equal(t, result.BestMatches[0].AvailabilityPercent, result.BestMatches[1].AvailabilityPercent)
-		assert.Greater(t, result.BestMatches[0].PreferredPercent, result.BestMatches[1].PreferredPercent)
+		assert.Greater(t, result.BestMatches[0].PreferenceScore, result.BestMatches[1].PreferenceScore)
 
 		mockEventRepo.AssertExpectations(t)
 		mockAvailRepo.AssertExpectations(t)
 		mockPrefRepo.AssertExpectations(t)
 	})
 
+	t.Run("GetRecommendations_AvoidWindowDemotesSlot", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID1 := uuid.New()
+		slotID2 := uuid.New()
+		participant1 := uuid.New()
+		participant2 := uuid.New()
+
+		now := time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Email: "alice@example.com"},
+				{ID: participant2, Email: "bob@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID1, StartTime: now, EndTime: now.Add(time.Hour)},
+				{ID: slotID2, StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID1, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID2, Status: model.AvailabilityStatusAvailable},
+		}
+
+		// alice likes the whole morning but would rather not meet at ten;
+		// bob has no preferences at all
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour), Level: model.PreferenceIdeal},
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: now, EndTime: now.Add(time.Hour), Level: model.PreferenceAvoid},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+
+		result, err := svc.ExplainRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.BestMatches, 2)
+
+		assert.Equal(t, slotID2, result.BestMatches[0].SlotID)
+		assert.Equal(t, float64(50), result.BestMatches[0].PreferenceScore)
+		assert.Equal(t, 1, result.BestMatches[0].PreferredCount)
+
+		avoided := result.BestMatches[1]
+		assert.Equal(t, slotID1, avoided.SlotID)
+		assert.Equal(t, float64(-50), avoided.PreferenceScore)
+		assert.Equal(t, 0, avoided.PreferredCount)
+		assert.Equal(t, 1, avoided.AvoidCount)
+		assert.Equal(t, model.PreferenceAvoid, avoided.Explain[0].Preference)
+		assert.False(t, avoided.Explain[0].Preferred)
+		assert.Empty(t, avoided.Explain[1].Preference)
+	})
+
+	t.Run("GetRecommendations_IfNeededCountsButScoresLower", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID1 := uuid.New()
+		slotID2 := uuid.New()
+		participant1 := uuid.New()
+		participant2 := uuid.New()
+
+		now := time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Email: "alice@example.com"},
+				{ID: participant2, Email: "bob@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID1, StartTime: now, EndTime: now.Add(time.Hour)},
+				{ID: slotID2, StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID1, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant2, SlotID: slotID1, Status: model.AvailabilityStatusIfNeeded},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID2, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant2, SlotID: slotID2, Status: model.AvailabilityStatusAvailable},
+		}
+
+		// both like the whole day, so only the if-needed answer stands
+		// between slot1 and a perfect match
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour)},
+			{ID: uuid.New(), Email: "bob@example.com", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour)},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.PerfectSlots, 1)
+		assert.Equal(t, slotID2, result.PerfectSlots[0].SlotID)
+
+		assert.Len(t, result.BestMatches, 1)
+		rec := result.BestMatches[0]
+		assert.Equal(t, slotID1, rec.SlotID)
+		assert.Equal(t, 2, rec.AvailableCount)
+		assert.Equal(t, 1, rec.IfNeededCount)
+		assert.Equal(t, float64(75), rec.AvailabilityPercent)
+		assert.False(t, rec.IsPerfectMatch)
+	})
+
+	t.Run("GetRecommendations_AcceptableWeighsLessThanIdeal", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID1 := uuid.New()
+		slotID2 := uuid.New()
+		participant1 := uuid.New()
+
+		now := time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)
+		event := &model.Event{
+			ID:           eventID,
+			Participants: []model.Participant{{ID: participant1, Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID1, StartTime: now, EndTime: now.Add(time.Hour)},
+				{ID: slotID2, StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID1, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID2, Status: model.AvailabilityStatusAvailable},
+		}
+
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: now.Add(-time.Hour), EndTime: now.Add(4 * time.Hour), Level: model.PreferenceAcceptable},
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour), Level: model.PreferenceIdeal},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		// both slots are preferred, so both are perfect, but the ideal one
+		// scores higher
+		assert.Len(t, result.PerfectSlots, 2)
+		assert.Equal(t, slotID2, result.PerfectSlots[0].SlotID)
+		assert.Equal(t, float64(100), result.PerfectSlots[0].PreferenceScore)
+		assert.Equal(t, float64(50), result.PerfectSlots[1].PreferenceScore)
+	})
+
 	t.Run("GetRecommendations_PreferredSlotWithDayOfWeek", func(t *testing.T) {
 		mockEventRepo := new(MockEventRepository)
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -856,7 +1200,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -895,7 +1239,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		assert.Len(t, result.BestMatches, 1)
 
 		assert.Equal(t, 0, result.BestMatches[0].PreferredCount)
-		assert.Equal(t, float64(0), result.BestMatches[0].PreferredPercent)
+		assert.Equal(t, float64(0), result.BestMatches[0].PreferenceScore)
 		assert.False(t, result.BestMatches[0].IsPerfectMatch)
 
 		mockEventRepo.AssertExpectations(t)
@@ -903,12 +1247,347 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockPrefRepo.AssertExpectations(t)
 	})
 
+	t.Run("GetRecommendations_RecurringPreferredSlotMatchesLaterOccurrence", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participant1 := uuid.New()
+
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		// the rule starts in winter; the slot is after the switch to summer
+		// time, so only a local-time expansion lands on it
+		first := time.Date(2026, 1, 6, 9, 0, 0, 0, berlin)
+		slotStart := time.Date(2026, 4, 7, 9, 30, 0, 0, berlin).UTC()
+		rule := "FREQ=WEEKLY;BYDAY=TU"
+
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Email: "alice@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID, StartTime: slotStart, EndTime: slotStart.Add(time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID, Status: model.AvailabilityStatusAvailable},
+		}
+
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: first.UTC(), EndTime: first.Add(2 * time.Hour).UTC(), Timezone: "Europe/Berlin", RRule: &rule},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.PerfectSlots, 1)
+		assert.Equal(t, 1, result.PerfectSlots[0].PreferredCount)
+	})
+
+	t.Run("GetRecommendations_RecurringPreferredSlotSkipsExDate", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participant1 := uuid.New()
+
+		first := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+		slotStart := time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC)
+		rule := "FREQ=DAILY"
+
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Email: "alice@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID, StartTime: slotStart, EndTime: slotStart.Add(time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID, Status: model.AvailabilityStatusAvailable},
+		}
+
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: first, EndTime: first.Add(2 * time.Hour), Timezone: "UTC", RRule: &rule, ExDates: []string{"2026-02-04"}},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.PerfectSlots)
+		assert.Len(t, result.BestMatches, 1)
+		assert.Equal(t, 0, result.BestMatches[0].PreferredCount)
+	})
+
+	t.Run("GetRecommendations_BlackoutOverridesAvailability", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+		mockBlackoutRepo := new(MockBlackoutRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, mockBlackoutRepo, noWorkingHours())
+
+		eventID := uuid.New()
+		slot1 := uuid.New()
+		slot2 := uuid.New()
+		participant1 := uuid.New()
+		participant2 := uuid.New()
+		blackoutID := uuid.New()
+
+		// 23:30 UTC on the 13th is already the 14th in Berlin
+		inLeave := time.Date(2026, 11, 13, 23, 30, 0, 0, time.UTC)
+		afterLeave := time.Date(2026, 11, 16, 10, 0, 0, 0, time.UTC)
+
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Name: "Alice", Email: "alice@example.com"},
+				{ID: participant2, Name: "Bob", Email: "bob@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slot1, StartTime: inLeave, EndTime: inLeave.Add(time.Hour)},
+				{ID: slot2, StartTime: afterLeave, EndTime: afterLeave.Add(time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slot1, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant2, SlotID: slot1, Status: model.AvailabilityStatusAvailable},
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slot2, Status: model.AvailabilityStatusAvailable},
+		}
+
+		blackouts := []model.Blackout{
+			{ID: blackoutID, Email: "Alice@Example.com", StartDate: "2026-11-03", EndDate: "2026-11-14", Timezone: "Europe/Berlin", Reason: "Annual leave"},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+		mockBlackoutRepo.On("GetByEmails", mock.Anything, []string{"alice@example.com", "bob@example.com"}, inLeave, afterLeave.Add(time.Hour)).Return(blackouts, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.BestMatches, 2)
+
+		byID := map[uuid.UUID]model.Recommendation{}
+		for _, rec := range result.BestMatches {
+			byID[rec.SlotID] = rec
+		}
+		assert.Equal(t, 1, byID[slot1].AvailableCount)
+		assert.Equal(t, []model.BlackoutConflict{
+			{ParticipantID: participant1, Name: "Alice", BlackoutID: blackoutID, Reason: "Annual leave"},
+		}, byID[slot1].Blackouts)
+		assert.Equal(t, 1, byID[slot2].AvailableCount)
+		assert.Empty(t, byID[slot2].Blackouts)
+
+		mockBlackoutRepo.AssertExpectations(t)
+	})
+
+	t.Run("ExplainRecommendations_ShowsBlackoutForUnansweredParticipant", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+		mockBlackoutRepo := new(MockBlackoutRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, mockBlackoutRepo, noWorkingHours())
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participant1 := uuid.New()
+		start := time.Date(2026, 11, 5, 9, 0, 0, 0, time.UTC)
+
+		event := &model.Event{
+			ID:            eventID,
+			Participants:  []model.Participant{{ID: participant1, Name: "Alice", Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{{ID: slotID, StartTime: start, EndTime: start.Add(time.Hour)}},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return([]model.Availability{}, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+		mockBlackoutRepo.On("GetByEmails", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Blackout{
+			{ID: uuid.New(), Email: "alice@example.com", StartDate: "2026-11-05", EndDate: "2026-11-05", Timezone: "UTC", Reason: "Conference"},
+		}, nil)
+
+		result, err := svc.ExplainRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		exp := result.BestMatches[0].Explain[0]
+		assert.False(t, exp.Responded)
+		assert.True(t, exp.BlackedOut)
+		assert.Equal(t, "Conference", exp.BlackoutReason)
+		assert.Len(t, result.BestMatches[0].Blackouts, 1)
+	})
+
+	t.Run("GetRecommendations_BlackoutRepoError", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+		mockBlackoutRepo := new(MockBlackoutRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, mockBlackoutRepo, noWorkingHours())
+
+		eventID := uuid.New()
+		now := time.Now().UTC()
+		event := &model.Event{
+			ID:            eventID,
+			Participants:  []model.Participant{{ID: uuid.New(), Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{{ID: uuid.New(), StartTime: now, EndTime: now.Add(time.Hour)}},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return([]model.Availability{}, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+		mockBlackoutRepo.On("GetByEmails", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("GetRecommendations_WorkingHoursPenalizeAndHardLimitsReject", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+		mockHoursRepo := new(MockWorkingHoursRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), mockHoursRepo)
+
+		eventID := uuid.New()
+		inHours := uuid.New()
+		evening := uuid.New()
+		tooLate := uuid.New()
+		participant1 := uuid.New()
+
+		// Wednesday; New York is UTC-5 in January
+		day := time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC)
+		at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+		event := &model.Event{
+			ID:           eventID,
+			Participants: []model.Participant{{ID: participant1, Name: "Alice", Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{
+				{ID: tooLate, StartTime: at(25), EndTime: at(26)}, // 20:00-21:00 local
+				{ID: evening, StartTime: at(23), EndTime: at(24)}, // 18:00-19:00 local
+				{ID: inHours, StartTime: at(15), EndTime: at(16)}, // 10:00-11:00 local
+			},
+		}
+
+		var availabilities []model.Availability
+		for _, slot := range event.ProposedSlots {
+			availabilities = append(availabilities, model.Availability{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slot.ID, Status: model.AvailabilityStatusAvailable})
+		}
+
+		notBefore, notAfter := "08:00", "20:00"
+		profiles := []model.WorkingHoursProfile{{
+			Email:     "alice@example.com",
+			Timezone:  "America/New_York",
+			Hours:     []model.WorkingHours{{DayOfWeek: 3, Start: "09:00", End: "17:00"}},
+			NotBefore: &notBefore,
+			NotAfter:  &notAfter,
+		}}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return([]model.AvailabilityRevision{}, nil)
+		mockHoursRepo.On("GetByEmails", mock.Anything, []string{"alice@example.com"}).Return(profiles, nil)
+
+		result, err := svc.ExplainRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.BestMatches, 2)
+		assert.Equal(t, inHours, result.BestMatches[0].SlotID)
+		assert.Equal(t, 0, result.BestMatches[0].OutsideHoursCount)
+		assert.Equal(t, evening, result.BestMatches[1].SlotID)
+		assert.Equal(t, 1, result.BestMatches[1].OutsideHoursCount)
+		assert.Equal(t, model.HoursViolationWorkingHours, result.BestMatches[1].Explain[0].OutsideHours)
+
+		assert.Len(t, result.Rejected, 1)
+		assert.Equal(t, tooLate, result.Rejected[0].SlotID)
+		assert.Equal(t, []model.HoursConflict{{
+			ParticipantID: participant1,
+			Name:          "Alice",
+			Violation:     model.HoursViolationHardLimits,
+			Timezone:      "America/New_York",
+			LocalStart:    "Wed 2026-01-07 20:00",
+			LocalEnd:      "Wed 2026-01-07 21:00",
+		}}, result.Rejected[0].HoursConflicts)
+	})
+
+	t.Run("GetRecommendations_OutsideWorkingHoursIsNotPerfect", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+		mockHoursRepo := new(MockWorkingHoursRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), mockHoursRepo)
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participant1 := uuid.New()
+		saturday := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+
+		event := &model.Event{
+			ID:            eventID,
+			Participants:  []model.Participant{{ID: participant1, Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{{ID: slotID, StartTime: saturday, EndTime: saturday.Add(time.Hour)}},
+		}
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID, Status: model.AvailabilityStatusAvailable},
+		}
+		preferredSlots := []model.PreferredSlot{
+			{ID: uuid.New(), Email: "alice@example.com", StartTime: saturday.Add(-time.Hour), EndTime: saturday.Add(2 * time.Hour)},
+		}
+		profiles := []model.WorkingHoursProfile{{
+			Email:    "alice@example.com",
+			Timezone: "UTC",
+			Hours:    []model.WorkingHours{{DayOfWeek: 1, Start: "09:00", End: "17:00"}},
+		}}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(preferredSlots, nil)
+		mockHoursRepo.On("GetByEmails", mock.Anything, mock.Anything).Return(profiles, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.PerfectSlots)
+		assert.Len(t, result.BestMatches, 1)
+		assert.Equal(t, 1, result.BestMatches[0].OutsideHoursCount)
+		assert.Empty(t, result.Rejected)
+	})
+
 	t.Run("GetRecommendations_AvailabilityRepoError", func(t *testing.T) {
 		mockEventRepo := new(MockEventRepository)
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		event := &model.Event{ID: eventID}
@@ -930,7 +1609,7 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		eventID := uuid.New()
 		slotID := uuid.New()
@@ -970,13 +1649,138 @@ func TestSchedulerServiceSuite(t *testing.T) {
 		mockPrefRepo.AssertExpectations(t)
 	})
 
+	t.Run("ExplainRecommendations_FlagsRecentlyChangedAnswers", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		participant1 := uuid.New()
+		participant2 := uuid.New()
+
+		slotStart := time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)
+		changedAt := time.Now().UTC().Add(-time.Hour)
+		event := &model.Event{
+			ID: eventID,
+			Participants: []model.Participant{
+				{ID: participant1, Name: "Alice", Email: "alice@example.com", SubmissionNote: "away that week"},
+				{ID: participant2, Name: "Bob", Email: "bob@example.com"},
+			},
+			ProposedSlots: []model.TimeSlot{
+				{ID: slotID, StartTime: slotStart, EndTime: slotStart.Add(time.Hour)},
+			},
+		}
+
+		availabilities := []model.Availability{
+			{ID: uuid.New(), EventID: eventID, ParticipantID: participant1, SlotID: slotID, Status: model.AvailabilityStatusUnavailable, Note: "dentist"},
+		}
+
+		revisions := []model.AvailabilityRevision{
+			{ParticipantID: participant1, SlotID: slotID, Revision: 1, Status: model.AvailabilityStatusAvailable, CreatedAt: changedAt.Add(-72 * time.Hour)},
+			{ParticipantID: participant1, SlotID: slotID, Revision: 2, Status: model.AvailabilityStatusUnavailable, CreatedAt: changedAt},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return(availabilities, nil)
+		mockAvailRepo.On("GetRevisionsByEventID", mock.Anything, eventID).Return(revisions, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+
+		result, err := svc.ExplainRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.BestMatches, 1)
+
+		explain := result.BestMatches[0].Explain
+		assert.Len(t, explain, 2)
+
+		assert.Equal(t, participant1, explain[0].ParticipantID)
+		assert.True(t, explain[0].Responded)
+		assert.Equal(t, model.AvailabilityStatusUnavailable, explain[0].Status)
+		assert.True(t, explain[0].RecentlyChanged)
+		assert.Equal(t, model.AvailabilityStatusAvailable, explain[0].PreviousStatus)
+		assert.Equal(t, "dentist", explain[0].Note)
+		assert.Equal(t, "away that week", explain[0].SubmissionNote)
+
+		assert.Equal(t, participant2, explain[1].ParticipantID)
+		assert.False(t, explain[1].Responded)
+		assert.False(t, explain[1].RecentlyChanged)
+
+		mockAvailRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetRecommendations_OmitsExplain", func(t *testing.T) {
+		mockEventRepo := new(MockEventRepository)
+		mockAvailRepo := new(MockAvailabilityRepository)
+		mockPrefRepo := new(MockPreferredSlotRepository)
+
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
+
+		eventID := uuid.New()
+		slotID := uuid.New()
+		now := time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)
+		event := &model.Event{
+			ID:            eventID,
+			Participants:  []model.Participant{{ID: uuid.New(), Email: "alice@example.com"}},
+			ProposedSlots: []model.TimeSlot{{ID: slotID, StartTime: now, EndTime: now.Add(time.Hour)}},
+		}
+
+		mockEventRepo.On("GetByID", mock.Anything, eventID).Return(event, nil)
+		mockAvailRepo.On("GetByEventID", mock.Anything, eventID).Return([]model.Availability{}, nil)
+		mockPrefRepo.On("GetByEmails", mock.Anything, mock.Anything).Return([]model.PreferredSlot{}, nil)
+
+		result, err := svc.GetRecommendations(context.Background(), eventID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.BestMatches[0].Explain)
+		mockAvailRepo.AssertNotCalled(t, "GetRevisionsByEventID", mock.Anything, mock.Anything)
+	})
+
 	t.Run("NewSchedulerService", func(t *testing.T) {
 		mockEventRepo := new(MockEventRepository)
 		mockAvailRepo := new(MockAvailabilityRepository)
 		mockPrefRepo := new(MockPreferredSlotRepository)
 
-		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo)
+		svc := NewSchedulerService(mockEventRepo, mockAvailRepo, mockPrefRepo, noBlackouts(), noWorkingHours())
 
 		assert.NotNil(t, svc)
 	})
 }
+
+func TestCheckRecurrence(t *testing.T) {
+	start := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	monday := 1
+
+	tests := []struct {
+		name  string
+		rule  string
+		dow   *int
+		field string
+	}{
+		{name: "weekly", rule: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "hourly", rule: "FREQ=HOURLY", field: "rrule"},
+		{name: "byhour", rule: "FREQ=DAILY;BYHOUR=10", field: "rrule"},
+		{name: "dtstart", rule: "DTSTART:20260202T090000Z\nRRULE:FREQ=DAILY", field: "rrule"},
+		{name: "garbage", rule: "FREQ=SOMETIMES", field: "rrule"},
+		{name: "with day_of_week", rule: "FREQ=WEEKLY", dow: &monday, field: "day_of_week"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			slot := &model.PreferredSlot{StartTime: start, EndTime: start.Add(time.Hour), Timezone: "UTC", RRule: &rule, DayOfWeek: tt.dow}
+
+			var v validation.Errors
+			checkRecurrence(&v, slot)
+
+			if tt.field == "" {
+				assert.True(t, v.Empty())
+				return
+			}
+			assert.False(t, v.Empty())
+			assert.Equal(t, tt.field, apperr.From(v.Err()).Fields[0].Field)
+		})
+	}
+}
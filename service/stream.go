@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/pubsub"
+	"github.com/ram-ks/meeting-service/tracing"
+)
+
+// recommendationSummarySize is how many of the best slots a summary lists.
+const recommendationSummarySize = 3
+
+// streamPublisher pushes updates to an event's live subscribers after a
+// mutation has succeeded. Subscribers can reload what they missed, so a failed
+// publish is logged rather than returned; it is a no-op without a publisher.
+type streamPublisher struct {
+	pub pubsub.Publisher
+}
+
+func (p streamPublisher) publish(ctx context.Context, msgType model.StreamEventType, eventID uuid.UUID, data interface{}) {
+	if p.pub == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode stream message", "type", msgType, "event_id", eventID, "error", err)
+		return
+	}
+
+	msg := model.StreamMessage{
+		Type:    msgType,
+		EventID: eventID,
+		Data:    payload,
+		At:      time.Now().UTC(),
+		Trace:   tracing.Inject(ctx),
+	}
+	if err := p.pub.Publish(ctx, msg); err != nil {
+		slog.ErrorContext(ctx, "failed to publish stream message", "type", msgType, "event_id", eventID, "error", err)
+	}
+}
+
+// RecommendationNotifier recomputes an event's recommendations whenever an
+// answer is submitted, updated or deleted on this instance, and its free
+// windows whenever free ranges are, and publishes a summary. Messages
+// relayed from other instances are skipped; their origin publishes its own.
+type RecommendationNotifier struct {
+	broker     pubsub.Broker
+	scheduler  SchedulerService
+	freeRanges FreeRangeService
+	stream     streamPublisher
+}
+
+func NewRecommendationNotifier(broker pubsub.Broker, scheduler SchedulerService, freeRanges FreeRangeService) *RecommendationNotifier {
+	return &RecommendationNotifier{broker: broker, scheduler: scheduler, freeRanges: freeRanges, stream: streamPublisher{pub: broker}}
+}
+
+// Run handles submissions until ctx is cancelled.
+func (n *RecommendationNotifier) Run(ctx context.Context) {
+	sub := n.broker.SubscribeAll()
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if msg.Origin != "" {
+				continue
+			}
+			switch msg.Type {
+			case model.StreamAvailabilitySubmitted, model.StreamAvailabilityUpdated, model.StreamAvailabilityDeleted:
+				n.notify(tracing.Extract(ctx, msg.Trace), msg.EventID)
+			case model.StreamFreeRangesSubmitted:
+				n.notifyFreeWindows(tracing.Extract(ctx, msg.Trace), msg.EventID)
+			}
+		}
+	}
+}
+
+func (n *RecommendationNotifier) notify(ctx context.Context, eventID uuid.UUID) {
+	ctx, span := tracer.Start(ctx, "RecommendationNotifier.notify", eventIDAttr(eventID))
+	defer span.End()
+
+	recs, err := n.scheduler.GetRecommendations(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to recompute recommendations", "event_id", eventID, "error", err)
+		return
+	}
+	n.stream.publish(ctx, model.StreamRecommendationsUpdated, eventID, summarize(recs))
+}
+
+func (n *RecommendationNotifier) notifyFreeWindows(ctx context.Context, eventID uuid.UUID) {
+	ctx, span := tracer.Start(ctx, "RecommendationNotifier.notifyFreeWindows", eventIDAttr(eventID))
+	defer span.End()
+
+	windows, err := n.freeRanges.GetFreeWindows(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to recompute free windows", "event_id", eventID, "error", err)
+		return
+	}
+
+	summary := model.FreeWindowSummary{WindowCount: len(windows.Windows), Top: windows.Windows}
+	if len(summary.Top) > recommendationSummarySize {
+		summary.Top = summary.Top[:recommendationSummarySize]
+	}
+	n.stream.publish(ctx, model.StreamFreeWindowsUpdated, eventID, summary)
+}
+
+func summarize(recs *model.RecommendationResponse) model.RecommendationSummary {
+	summary := model.RecommendationSummary{
+		PerfectSlotCount: len(recs.PerfectSlots),
+		Top:              []model.RecommendationSummaryItem{},
+	}
+
+	ranked := append(append([]model.Recommendation{}, recs.PerfectSlots...), recs.BestMatches...)
+	for _, rec := range ranked {
+		if len(summary.Top) == recommendationSummarySize {
+			break
+		}
+		summary.Top = append(summary.Top, model.RecommendationSummaryItem{
+			SlotID:              rec.SlotID,
+			StartTime:           rec.Slot.StartTime,
+			EndTime:             rec.Slot.EndTime,
+			AvailableCount:      rec.AvailableCount,
+			TotalParticipants:   rec.TotalParticipants,
+			AvailabilityPercent: rec.AvailabilityPercent,
+			IsPerfectMatch:      rec.IsPerfectMatch,
+		})
+	}
+	return summary
+}
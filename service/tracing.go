@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ram-ks/meeting-service/service")
+
+func eventIDAttr(id uuid.UUID) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("meeting.event_id", id.String()))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+// slotTimes is the parsed form of a start/end/timezone triple, shared by
+// event slots and preferred slots.
+type slotTimes struct {
+	Start    time.Time
+	End      time.Time
+	Timezone string
+}
+
+// parseSlotTimes parses a start/end pair in timezone, reporting problems
+// against the fields under prefix. ok is false if anything was recorded.
+func parseSlotTimes(v *validation.Errors, prefix, start, end, timezone string) (slotTimes, bool) {
+	loc, ok := v.Location(prefix+"timezone", timezone)
+	if !ok {
+		return slotTimes{}, false
+	}
+	startTime, startOK := v.Time(prefix+"start_time", start, loc)
+	endTime, endOK := v.Time(prefix+"end_time", end, loc)
+	if !startOK || !endOK {
+		return slotTimes{}, false
+	}
+	if !v.Range(prefix+"end_time", "start_time", startTime, endTime) {
+		return slotTimes{}, false
+	}
+	return slotTimes{Start: startTime, End: endTime, Timezone: timezone}, true
+}
+
+// updateSlotTimes applies optional changes on top of cur. New times are read
+// in the new timezone if one is given, and the resulting range is checked.
+func updateSlotTimes(v *validation.Errors, cur slotTimes, start, end, timezone *string) (slotTimes, bool) {
+	next := cur
+	if timezone != nil {
+		next.Timezone = *timezone
+	}
+	loc, ok := v.Location("timezone", next.Timezone)
+	if !ok {
+		return cur, false
+	}
+
+	ok = true
+	if start != nil {
+		t, parsed := v.Time("start_time", *start, loc)
+		next.Start, ok = t, ok && parsed
+	}
+	if end != nil {
+		t, parsed := v.Time("end_time", *end, loc)
+		next.End, ok = t, ok && parsed
+	}
+	if !ok || !v.Range("end_time", "start_time", next.Start, next.End) {
+		return cur, false
+	}
+	return next, true
+}
+
+// checkUniqueEmails reports participants whose email, ignoring case, repeats
+// an earlier one.
+func checkUniqueEmails(v *validation.Errors, participants []model.CreateParticipantRequest) {
+	seen := make(map[string]int, len(participants))
+	for i, p := range participants {
+		email := strings.ToLower(strings.TrimSpace(p.Email))
+		if first, dup := seen[email]; dup {
+			v.Add(validation.Index("participants", i)+".email", "duplicates %s.email", validation.Index("participants", first))
+			continue
+		}
+		seen[email] = i
+	}
+}
+
+// parseAnswerWindow checks an availability answer's status and optional
+// partial window, reporting against the fields under prefix.
+func parseAnswerWindow(v *validation.Errors, prefix string, status model.AvailabilityStatus, from, to *string) (*time.Time, *time.Time) {
+	v.Status(prefix+"status", status)
+
+	var fromTime, toTime *time.Time
+	if from != nil {
+		if t, ok := v.RFC3339(prefix+"available_from", *from); ok {
+			fromTime = &t
+		}
+	}
+	if to != nil {
+		if t, ok := v.RFC3339(prefix+"available_to", *to); ok {
+			toTime = &t
+		}
+	}
+	if fromTime != nil && toTime != nil {
+		v.Range(prefix+"available_to", "available_from", *fromTime, *toTime)
+	}
+	return fromTime, toTime
+}
+
+// parseSearchWindow reads a flexible event's search window, reporting
+// against the fields under prefix.
+func parseSearchWindow(v *validation.Errors, prefix string, req model.SearchWindowRequest) (*model.SearchWindow, bool) {
+	start, startOK := v.RFC3339(prefix+"start", req.Start)
+	end, endOK := v.RFC3339(prefix+"end", req.End)
+	if !startOK || !endOK || !v.Range(prefix+"end", "start", start, end) {
+		return nil, false
+	}
+	return &model.SearchWindow{Start: start.UTC(), End: end.UTC()}, true
+}
+
+// parseRespondBy reads a response deadline, which must still be ahead of
+// now.
+func parseRespondBy(v *validation.Errors, value string, now time.Time) *time.Time {
+	t, ok := v.RFC3339("respond_by", value)
+	if !ok {
+		return nil
+	}
+	if !t.After(now) {
+		v.Add("respond_by", "must be in the future")
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// checkFlexibleEvent checks that a flexible event's duration is one the
+// scheduler can search for, and that it fits in the search window. Events
+// without a search window keep duration as free text.
+func checkFlexibleEvent(v *validation.Errors, duration string, window *model.SearchWindow) {
+	if window == nil {
+		return
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil || d <= 0 {
+		v.Add("duration", "must be a duration such as 30m or 1h30m when search_window is set")
+		return
+	}
+	if window.End.Sub(window.Start) < d {
+		v.Add("search_window.end", "must leave room for the duration after start")
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/ram-ks/meeting-service/repository"
+	"github.com/ram-ks/meeting-service/validation"
+)
+
+type WorkingHoursService interface {
+	Put(ctx context.Context, email string, req model.PutWorkingHoursRequest) (*model.WorkingHoursProfile, error)
+	GetByEmail(ctx context.Context, email string) (*model.WorkingHoursProfile, error)
+	Delete(ctx context.Context, email string) error
+}
+
+type workingHoursService struct {
+	repo  repository.WorkingHoursRepository
+	audit auditRecorder
+}
+
+func NewWorkingHoursService(repo repository.WorkingHoursRepository, auditRepo repository.AuditRepository) WorkingHoursService {
+	return &workingHoursService{
+		repo:  repo,
+		audit: auditRecorder{repo: auditRepo},
+	}
+}
+
+func (s *workingHoursService) Put(ctx context.Context, email string, req model.PutWorkingHoursRequest) (*model.WorkingHoursProfile, error) {
+	var v validation.Errors
+	if _, err := mail.ParseAddress(email); err != nil {
+		v.Add("email", "must be a valid email address")
+	}
+	now := time.Now().UTC()
+	profile := &model.WorkingHoursProfile{
+		ID:        uuid.New(),
+		Email:     strings.ToLower(email),
+		Timezone:  req.Timezone,
+		Hours:     req.Hours,
+		NotBefore: req.NotBefore,
+		NotAfter:  req.NotAfter,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if profile.Hours == nil {
+		profile.Hours = []model.WorkingHours{}
+	}
+	if checkWorkingHours(&v, profile); !v.Empty() {
+		return nil, v.Err()
+	}
+
+	before, err := s.repo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := s.repo.Upsert(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		s.audit.record(ctx, model.AuditActionCreate, model.AuditEntityWorkingHours, profile.ID, nil, nil, profile)
+	} else {
+		s.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityWorkingHours, profile.ID, nil, before, profile)
+	}
+	return profile, nil
+}
+
+func (s *workingHoursService) GetByEmail(ctx context.Context, email string) (*model.WorkingHoursProfile, error) {
+	profile, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, notFound(err, ErrWorkingHoursNotFound)
+	}
+	return profile, nil
+}
+
+func (s *workingHoursService) Delete(ctx context.Context, email string) error {
+	profile, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return notFound(err, ErrWorkingHoursNotFound)
+	}
+
+	if err := s.repo.Delete(ctx, email); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, model.AuditActionDelete, model.AuditEntityWorkingHours, profile.ID, nil, profile, nil)
+	return nil
+}
+
+// checkWorkingHours validates a profile's timezone, hours and hard limits.
+// Working hours must fall within the hard limits.
+func checkWorkingHours(v *validation.Errors, profile *model.WorkingHoursProfile) {
+	v.Location("timezone", profile.Timezone)
+
+	notBefore, notAfter := 0, minutesPerDay
+	if profile.NotBefore != nil {
+		notBefore, _ = parseClock(*profile.NotBefore)
+	}
+	if profile.NotAfter != nil {
+		notAfter, _ = parseClock(*profile.NotAfter)
+	}
+	if notAfter <= notBefore {
+		v.Add("not_after", "must be after not_before")
+		return
+	}
+
+	for i, h := range profile.Hours {
+		field := validation.Index("hours", i)
+		start, startErr := parseClock(h.Start)
+		end, endErr := parseClock(h.End)
+		if startErr != nil || endErr != nil {
+			v.Add(field, "must have start and end times such as 09:30")
+			continue
+		}
+		if end <= start {
+			v.Add(field+".end", "must be after start")
+			continue
+		}
+		if start < notBefore || end > notAfter {
+			v.Add(field, "must be within not_before and not_after")
+		}
+	}
+}
+
+const minutesPerDay = 24 * 60
+
+// parseClock reads an HH:MM time of day as minutes since midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse(validation.ClockLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+type minuteRange struct {
+	start int
+	end   int
+}
+
+// hoursProfile is a WorkingHoursProfile parsed for checking slots against.
+type hoursProfile struct {
+	timezone  string
+	loc       *time.Location
+	hours     map[time.Weekday][]minuteRange
+	limited   bool
+	notBefore int
+	notAfter  int
+}
+
+func newHoursProfile(profile model.WorkingHoursProfile) (hoursProfile, error) {
+	loc, err := time.LoadLocation(profile.Timezone)
+	if err != nil {
+		return hoursProfile{}, err
+	}
+	h := hoursProfile{
+		timezone:  profile.Timezone,
+		loc:       loc,
+		hours:     make(map[time.Weekday][]minuteRange),
+		limited:   profile.NotBefore != nil || profile.NotAfter != nil,
+		notBefore: 0,
+		notAfter:  minutesPerDay,
+	}
+	if profile.NotBefore != nil {
+		if h.notBefore, err = parseClock(*profile.NotBefore); err != nil {
+			return hoursProfile{}, err
+		}
+	}
+	if profile.NotAfter != nil {
+		if h.notAfter, err = parseClock(*profile.NotAfter); err != nil {
+			return hoursProfile{}, err
+		}
+	}
+	for _, wh := range profile.Hours {
+		start, err := parseClock(wh.Start)
+		if err != nil {
+			return hoursProfile{}, err
+		}
+		end, err := parseClock(wh.End)
+		if err != nil {
+			return hoursProfile{}, err
+		}
+		day := time.Weekday(wh.DayOfWeek)
+		h.hours[day] = append(h.hours[day], minuteRange{start: start, end: end})
+	}
+	return h, nil
+}
+
+// check returns how slot falls outside the profile, or "" if it doesn't. A
+// profile with no hours only has its hard limits checked.
+func (h hoursProfile) check(slot model.TimeSlot) model.HoursViolation {
+	start, end := slot.StartTime.In(h.loc), slot.EndTime.In(h.loc)
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+
+	sameDay := start.YearDay() == end.YearDay() && start.Year() == end.Year()
+	if !sameDay && endMin == 0 && end.Sub(start) <= 24*time.Hour {
+		// ending at midnight still ends on the start's day
+		sameDay, endMin = true, minutesPerDay
+	}
+
+	if h.limited && (!sameDay || startMin < h.notBefore || endMin > h.notAfter) {
+		return model.HoursViolationHardLimits
+	}
+	if len(h.hours) == 0 {
+		return ""
+	}
+	if sameDay {
+		for _, r := range h.hours[start.Weekday()] {
+			if startMin >= r.start && endMin <= r.end {
+				return ""
+			}
+		}
+	}
+	return model.HoursViolationWorkingHours
+}
+
+// allowedWindows is the part of span inside the profile's hard limits, day
+// by day in its timezone; without limits it is all of span.
+func (h hoursProfile) allowedWindows(span timeWindow) []timeWindow {
+	if !h.limited {
+		return []timeWindow{span}
+	}
+	var allowed []timeWindow
+	local := span.start.In(h.loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, h.loc); day.Before(span.end); day = day.AddDate(0, 0, 1) {
+		w := timeWindow{
+			start: latest(span.start, time.Date(day.Year(), day.Month(), day.Day(), 0, h.notBefore, 0, 0, h.loc)),
+			end:   earliest(span.end, time.Date(day.Year(), day.Month(), day.Day(), 0, h.notAfter, 0, 0, h.loc)),
+		}
+		if w.end.After(w.start) {
+			allowed = append(allowed, w)
+		}
+	}
+	return allowed
+}
+
+// conflict describes slot in the profile's local time for the result.
+func (h hoursProfile) conflict(slot model.TimeSlot, p model.Participant, violation model.HoursViolation) model.HoursConflict {
+	const layout = "Mon 2006-01-02 15:04"
+	return model.HoursConflict{
+		ParticipantID: p.ID,
+		Name:          p.Name,
+		Violation:     violation,
+		Timezone:      h.timezone,
+		LocalStart:    slot.StartTime.In(h.loc).Format(layout),
+		LocalEnd:      slot.EndTime.In(h.loc).Format(layout),
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkingHoursSuite(t *testing.T) {
+	t.Run("Check", func(t *testing.T) {
+		notBefore, notAfter := "08:00", "20:00"
+		profile, err := newHoursProfile(model.WorkingHoursProfile{
+			Timezone:  "Europe/London",
+			Hours:     []model.WorkingHours{{DayOfWeek: 1, Start: "09:00", End: "17:00"}},
+			NotBefore: &notBefore,
+			NotAfter:  &notAfter,
+		})
+		assert.NoError(t, err)
+
+		london, _ := time.LoadLocation("Europe/London")
+		slot := func(start time.Time, length time.Duration) model.TimeSlot {
+			return model.TimeSlot{StartTime: start.UTC(), EndTime: start.Add(length).UTC()}
+		}
+
+		tests := []struct {
+			name string
+			slot model.TimeSlot
+			want model.HoursViolation
+		}{
+			{"winter monday", slot(time.Date(2026, 1, 5, 9, 0, 0, 0, london), time.Hour), ""},
+			{"summer monday, local time", slot(time.Date(2026, 7, 6, 16, 0, 0, 0, london), time.Hour), ""},
+			{"runs past working hours", slot(time.Date(2026, 1, 5, 16, 30, 0, 0, london), time.Hour), model.HoursViolationWorkingHours},
+			{"weekend", slot(time.Date(2026, 1, 10, 10, 0, 0, 0, london), time.Hour), model.HoursViolationWorkingHours},
+			{"ends at not_after", slot(time.Date(2026, 1, 6, 19, 0, 0, 0, london), time.Hour), model.HoursViolationWorkingHours},
+			{"before not_before", slot(time.Date(2026, 1, 5, 7, 30, 0, 0, london), time.Hour), model.HoursViolationHardLimits},
+			{"crosses midnight", slot(time.Date(2026, 1, 5, 23, 30, 0, 0, london), time.Hour), model.HoursViolationHardLimits},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, profile.check(tt.slot))
+			})
+		}
+	})
+
+	t.Run("Check_EndingAtMidnightWithoutLimits", func(t *testing.T) {
+		profile, err := newHoursProfile(model.WorkingHoursProfile{Timezone: "UTC"})
+		assert.NoError(t, err)
+		start := time.Date(2026, 1, 9, 23, 0, 0, 0, time.UTC)
+		assert.Equal(t, model.HoursViolation(""), profile.check(model.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)}))
+	})
+
+	t.Run("Put_ReportsEveryProblem", func(t *testing.T) {
+		mockRepo := new(MockWorkingHoursRepository)
+		svc := NewWorkingHoursService(mockRepo, nil)
+
+		notBefore := "08:00"
+		_, err := svc.Put(context.Background(), "not-an-email", model.PutWorkingHoursRequest{
+			Timezone: "UTC",
+			Hours: []model.WorkingHours{
+				{DayOfWeek: 1, Start: "17:00", End: "09:00"},
+				{DayOfWeek: 2, Start: "07:00", End: "15:00"},
+			},
+			NotBefore: &notBefore,
+		})
+
+		assert.ErrorIs(t, err, apperr.ErrValidation)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "email", Message: "must be a valid email address"},
+			{Field: "hours[0].end", Message: "must be after start"},
+			{Field: "hours[1]", Message: "must be within not_before and not_after"},
+		}, apperr.From(err).Fields)
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Put_UpdatesExistingProfile", func(t *testing.T) {
+		mockRepo := new(MockWorkingHoursRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		svc := NewWorkingHoursService(mockRepo, mockAuditRepo)
+
+		existing := &model.WorkingHoursProfile{ID: uuid.New(), Email: "alice@example.com", Timezone: "UTC", Hours: []model.WorkingHours{}}
+		mockRepo.On("GetByEmail", mock.Anything, "Alice@Example.com").Return(existing, nil)
+		mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(p *model.WorkingHoursProfile) bool {
+			return p.Email == "alice@example.com" && p.Timezone == "Asia/Tokyo"
+		})).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+			return e.Action == model.AuditActionUpdate && e.EntityType == model.AuditEntityWorkingHours
+		})).Return(nil)
+
+		profile, err := svc.Put(context.Background(), "Alice@Example.com", model.PutWorkingHoursRequest{Timezone: "Asia/Tokyo"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.WorkingHours{}, profile.Hours)
+		mockRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestPreferredSlotBootstrap(t *testing.T) {
+	newService := func(repo *MockPreferredSlotRepository, hoursRepo *MockWorkingHoursRepository, now time.Time) *preferredSlotService {
+		svc := NewPreferredSlotService(repo, hoursRepo, nil).(*preferredSlotService)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("GroupsDaysWithTheSameHours", func(t *testing.T) {
+		mockRepo := new(MockPreferredSlotRepository)
+		mockHoursRepo := new(MockWorkingHoursRepository)
+		// Saturday evening UTC is already Sunday in Sydney
+		svc := newService(mockRepo, mockHoursRepo, time.Date(2026, 3, 7, 20, 0, 0, 0, time.UTC))
+
+		mockHoursRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(&model.WorkingHoursProfile{
+			Email:    "alice@example.com",
+			Timezone: "Australia/Sydney",
+			Hours: []model.WorkingHours{
+				{DayOfWeek: 3, Start: "09:00", End: "17:00"},
+				{DayOfWeek: 1, Start: "09:00", End: "17:00"},
+				{DayOfWeek: 5, Start: "09:00", End: "13:00"},
+			},
+		}, nil)
+		mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return([]model.PreferredSlot{}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		slots, err := svc.Bootstrap(context.Background(), "alice@example.com")
+
+		assert.NoError(t, err)
+		assert.Len(t, slots, 2)
+		sydney, _ := time.LoadLocation("Australia/Sydney")
+
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,WE", *slots[0].RRule)
+		assert.Equal(t, time.Date(2026, 3, 9, 9, 0, 0, 0, sydney).UTC(), slots[0].StartTime)
+		assert.Equal(t, time.Date(2026, 3, 9, 17, 0, 0, 0, sydney).UTC(), slots[0].EndTime)
+
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=FR", *slots[1].RRule)
+		assert.Equal(t, time.Date(2026, 3, 13, 9, 0, 0, 0, sydney).UTC(), slots[1].StartTime)
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("RefusesWhenSlotsExist", func(t *testing.T) {
+		mockRepo := new(MockPreferredSlotRepository)
+		mockHoursRepo := new(MockWorkingHoursRepository)
+		svc := newService(mockRepo, mockHoursRepo, time.Now())
+
+		mockHoursRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(&model.WorkingHoursProfile{
+			Timezone: "UTC",
+			Hours:    []model.WorkingHours{{DayOfWeek: 1, Start: "09:00", End: "17:00"}},
+		}, nil)
+		mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return([]model.PreferredSlot{{ID: uuid.New()}}, nil)
+
+		_, err := svc.Bootstrap(context.Background(), "alice@example.com")
+
+		assert.ErrorIs(t, err, ErrPreferredSlotsExist)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NoProfile", func(t *testing.T) {
+		mockHoursRepo := new(MockWorkingHoursRepository)
+		svc := newService(new(MockPreferredSlotRepository), mockHoursRepo, time.Now())
+
+		mockHoursRepo.On("GetByEmail", mock.Anything, "bob@example.com").Return(nil, apperr.ErrNotFound)
+
+		_, err := svc.Bootstrap(context.Background(), "bob@example.com")
+
+		assert.ErrorIs(t, err, ErrWorkingHoursNotFound)
+	})
+}
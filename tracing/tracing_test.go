@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingSuite(t *testing.T) {
+	t.Run("InjectExtractRoundTrip", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), ExporterNone)
+		require.NoError(t, err)
+		defer shutdown(context.Background())
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+		carrier := Inject(ctx)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", carrier["traceparent"])
+
+		got := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+		assert.Equal(t, traceID, got.TraceID())
+		assert.Equal(t, spanID, got.SpanID())
+		assert.True(t, got.IsRemote())
+	})
+
+	t.Run("NoTraceNoCarrier", func(t *testing.T) {
+		assert.Nil(t, Inject(context.Background()))
+	})
+
+	t.Run("RejectsUnknownExporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), "zipkin")
+		assert.Error(t, err)
+	})
+}
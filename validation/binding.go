@@ -0,0 +1,165 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// The tags are registered on gin's validator at init so that every binding,
+// including in controller tests, understands them.
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Report fields by their JSON (or query) name rather than the Go name
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(f.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	mustRegister(v, "timezone", func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		_, err := time.LoadLocation(name)
+		return name != "" && err == nil
+	})
+	mustRegister(v, "timestamp", func(fl validator.FieldLevel) bool {
+		_, ok := ParseTime(fl.Field().String(), time.UTC)
+		return ok
+	})
+	mustRegister(v, "rfc3339", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(time.RFC3339, fl.Field().String())
+		return err == nil
+	})
+	mustRegister(v, "date", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(DateLayout, fl.Field().String())
+		return err == nil
+	})
+	mustRegister(v, "clock", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(ClockLayout, fl.Field().String())
+		return err == nil
+	})
+	mustRegister(v, "availability_status", func(fl validator.FieldLevel) bool {
+		return model.AvailabilityStatus(fl.Field().String()).IsValid()
+	})
+	mustRegister(v, "event_status", func(fl validator.FieldLevel) bool {
+		return model.EventStatus(fl.Field().String()).IsValid()
+	})
+}
+
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic(fmt.Sprintf("validation: register %q: %v", tag, err))
+	}
+}
+
+// FromBindError converts an error from gin's ShouldBind* into an *apperr.Error.
+// Tag failures become validation_failed with one entry per field; malformed
+// bodies become a plain bad request.
+func FromBindError(err error) *apperr.Error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]apperr.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, apperr.FieldError{Field: fieldPath(fe), Message: message(fe)})
+		}
+		return apperr.ErrValidation.WithFields(fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperr.ErrValidation.WithFields(apperr.FieldError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be a %s", typeErr.Type.String()),
+		})
+	}
+
+	return apperr.BadRequest("malformed request body").Wrap(err)
+}
+
+// fieldPath drops the root struct name from the namespace, turning
+// "CreateEventRequest.proposed_slots[0].end_time" into
+// "proposed_slots[0].end_time".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return sizeMessage(fe, "at least")
+	case "max":
+		return sizeMessage(fe, "at most")
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "timezone":
+		return "must be a valid IANA timezone"
+	case "timestamp":
+		return "must be a timestamp such as 2006-01-02T15:04:05"
+	case "rfc3339":
+		return "must be an RFC 3339 timestamp"
+	case "date":
+		return "must be a date such as 2006-01-02"
+	case "clock":
+		return "must be a time of day such as 09:30"
+	case "availability_status":
+		return availabilityStatusMessage()
+	case "event_status":
+		return eventStatusMessage()
+	default:
+		return fmt.Sprintf("failed %s validation", fe.Tag())
+	}
+}
+
+func sizeMessage(fe validator.FieldError, bound string) string {
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Map:
+		return fmt.Sprintf("must contain %s %s item(s)", bound, fe.Param())
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters", bound, fe.Param())
+	default:
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	}
+}
+
+func availabilityStatusMessage() string {
+	names := make([]string, len(model.AvailabilityStatuses))
+	for i, s := range model.AvailabilityStatuses {
+		names[i] = string(s)
+	}
+	return "must be one of " + strings.Join(names, ", ")
+}
+
+func eventStatusMessage() string {
+	names := make([]string, len(model.EventStatuses))
+	for i, s := range model.EventStatuses {
+		names[i] = string(s)
+	}
+	return "must be one of " + strings.Join(names, ", ")
+}
@@ -0,0 +1,116 @@
+// Package validation holds the request checks shared by the event, slot,
+// availability and preferred-slot endpoints. Static rules are custom gin
+// validator tags (see binding.go); rules that need parsed values, such as an
+// end time after its start, are collected by services with Errors.
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ram-ks/meeting-service/apperr"
+	"github.com/ram-ks/meeting-service/model"
+)
+
+// TimeLayouts are the accepted wall-clock formats for slot times. Times
+// without an offset are read in the slot's timezone.
+var TimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// DateLayout is the format of calendar dates, such as recurrence exceptions.
+const DateLayout = "2006-01-02"
+
+// ClockLayout is the format of local times of day, such as working hours.
+const ClockLayout = "15:04"
+
+// ParseTime parses value using TimeLayouts in loc and returns it in UTC.
+func ParseTime(value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Errors collects field errors so a request reports every problem at once
+// instead of failing on the first.
+type Errors struct {
+	fields []apperr.FieldError
+}
+
+func (e *Errors) Add(field, format string, args ...interface{}) {
+	e.fields = append(e.fields, apperr.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *Errors) Empty() bool {
+	return len(e.fields) == 0
+}
+
+// Err returns nil when nothing was collected, otherwise a validation_failed
+// error carrying the fields.
+func (e *Errors) Err() error {
+	if e.Empty() {
+		return nil
+	}
+	return apperr.ErrValidation.WithFields(e.fields...)
+}
+
+// Location loads an IANA timezone, recording an error against field when it
+// is unknown.
+func (e *Errors) Location(field, name string) (*time.Location, bool) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" {
+		e.Add(field, "must be a valid IANA timezone")
+		return nil, false
+	}
+	return loc, true
+}
+
+// Time parses value in loc, recording an error against field on failure.
+func (e *Errors) Time(field, value string, loc *time.Location) (time.Time, bool) {
+	t, ok := ParseTime(value, loc)
+	if !ok {
+		e.Add(field, "must be a timestamp such as 2006-01-02T15:04:05")
+	}
+	return t, ok
+}
+
+// RFC3339 parses an absolute timestamp, recording an error against field on
+// failure.
+func (e *Errors) RFC3339(field, value string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		e.Add(field, "must be an RFC 3339 timestamp")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Range checks that end is after start, reporting against endField.
+func (e *Errors) Range(endField, startField string, start, end time.Time) bool {
+	if !end.After(start) {
+		e.Add(endField, "must be after %s", startField)
+		return false
+	}
+	return true
+}
+
+// Status records an error against field for an unknown availability status.
+func (e *Errors) Status(field string, status model.AvailabilityStatus) bool {
+	if !status.IsValid() {
+		e.Add(field, "%s", availabilityStatusMessage())
+		return false
+	}
+	return true
+}
+
+// Index formats a path element for a slice field, e.g. Index("slots", 2)
+// gives "slots[2]".
+func Index(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}